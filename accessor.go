@@ -0,0 +1,133 @@
+package goasterix
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrItemNotFound reports that an item is absent of a record.
+	ErrItemNotFound = errors.New("[ASTERIX] item not found in the record")
+
+	// ErrItemType reports that an item is not of the type requested (e.g. Record.Fixed of a Compound item).
+	ErrItemType = errors.New("[ASTERIX] item is not of the type requested")
+)
+
+// Item returns the item frn of the record, or ErrItemNotFound.
+func (rec *Record) Item(frn uint8) (*Item, error) {
+	for i := range rec.Items {
+		if rec.Items[i].Meta.FRN == frn {
+			return &rec.Items[i], nil
+		}
+	}
+	return nil, ErrItemNotFound
+}
+
+// Has returns true if the item frn is present in the record.
+func (rec *Record) Has(frn uint8) bool {
+	_, err := rec.Item(frn)
+	return err == nil
+}
+
+// Lookup returns the item of the record named dataItem (DataItem of the UAP), or ErrItemNotFound.
+// A data subfield of a Compound item (or of a RE or SP item with sub-profile) is named by the name of the item
+// followed by "/" and its own name, e.g. "I062/380/ADR".
+func (rec *Record) Lookup(dataItem string) (*Item, error) {
+	for i := range rec.Items {
+		item := &rec.Items[i]
+		if item.Meta.DataItem == dataItem {
+			return item, nil
+		}
+		if item.Meta.DataItem != "" && strings.HasPrefix(dataItem, item.Meta.DataItem+"/") {
+			if sub := subItem(item, dataItem[len(item.Meta.DataItem)+1:]); sub != nil {
+				return sub, nil
+			}
+		}
+	}
+	return nil, ErrItemNotFound
+}
+
+// HasItem returns true if the item named dataItem is present in the record, see Lookup.
+func (rec *Record) HasItem(dataItem string) bool {
+	_, err := rec.Lookup(dataItem)
+	return err == nil
+}
+
+// Fixed returns the Fixed item named dataItem, see Lookup. It returns ErrItemType if the item is not Fixed.
+func (rec *Record) Fixed(dataItem string) (*Fixed, error) {
+	item, err := rec.Lookup(dataItem)
+	if err != nil {
+		return nil, err
+	}
+	if item.Fixed == nil {
+		return nil, ErrItemType
+	}
+	return item.Fixed, nil
+}
+
+// Extended returns the Extended item named dataItem, see Fixed.
+func (rec *Record) Extended(dataItem string) (*Extended, error) {
+	item, err := rec.Lookup(dataItem)
+	if err != nil {
+		return nil, err
+	}
+	if item.Extended == nil {
+		return nil, ErrItemType
+	}
+	return item.Extended, nil
+}
+
+// Explicit returns the Explicit item named dataItem, see Fixed.
+func (rec *Record) Explicit(dataItem string) (*Explicit, error) {
+	item, err := rec.Lookup(dataItem)
+	if err != nil {
+		return nil, err
+	}
+	if item.Explicit == nil {
+		return nil, ErrItemType
+	}
+	return item.Explicit, nil
+}
+
+// Repetitive returns the Repetitive item named dataItem, see Fixed.
+func (rec *Record) Repetitive(dataItem string) (*Repetitive, error) {
+	item, err := rec.Lookup(dataItem)
+	if err != nil {
+		return nil, err
+	}
+	if item.Repetitive == nil {
+		return nil, ErrItemType
+	}
+	return item.Repetitive, nil
+}
+
+// Compound returns the Compound item named dataItem, see Fixed.
+func (rec *Record) Compound(dataItem string) (*Compound, error) {
+	item, err := rec.Lookup(dataItem)
+	if err != nil {
+		return nil, err
+	}
+	if item.Compound == nil {
+		return nil, ErrItemType
+	}
+	return item.Compound, nil
+}
+
+// subItem returns the data subfield name of a Compound, RE or SP item, nil if absent.
+func subItem(item *Item, name string) *Item {
+	var cp *Compound
+	switch {
+	case item.Compound != nil:
+		cp = item.Compound
+	case item.SP != nil && item.SP.Compound != nil:
+		cp = item.SP.Compound
+	default:
+		return nil
+	}
+	for i := range cp.Secondary {
+		if cp.Secondary[i].Meta.DataItem == name {
+			return &cp.Secondary[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package goasterix
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mokhtarimokhtar/goasterix/uap"
+	"github.com/mokhtarimokhtar/goasterix/util"
+)
+
+func TestRecord_Lookup(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		dataItem     string
+		payload      []byte
+		err          error
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "item",
+			dataItem:     "I062/105",
+			payload:      []byte{0x00, 0x8e, 0x6f, 0x3e, 0x00, 0x17, 0xd0, 0x96},
+			err:          nil,
+		},
+		{
+			TestCaseName: "compound subfield",
+			dataItem:     "I062/380/ADR",
+			payload:      []byte{0x87, 0x30, 0x4a},
+			err:          nil,
+		},
+		{
+			TestCaseName: "subfield of the same name in two compound items",
+			dataItem:     "I062/295/MHG",
+			payload:      []byte{0x14},
+			err:          nil,
+		},
+		{
+			TestCaseName: "absent item",
+			dataItem:     "I062/245",
+			payload:      nil,
+			err:          ErrItemNotFound,
+		},
+		{
+			TestCaseName: "absent subfield",
+			dataItem:     "I062/380/SAL",
+			payload:      nil,
+			err:          ErrItemNotFound,
+		},
+		{
+			TestCaseName: "subfield of a fixed item",
+			dataItem:     "I062/105/LAT",
+			payload:      nil,
+			err:          ErrItemNotFound,
+		},
+	}
+	data, _ := util.HexStringToByte(cat062Len1351)
+	db, _, _ := NewDecoder().DecodeDataBlock(data)
+	rec := db.Records[0]
+
+	for _, row := range dataSet {
+		// Act
+		item, err := rec.Lookup(row.dataItem)
+
+		// Assert
+		if err != row.err || (err == nil && !bytes.Equal(item.Payload(), row.payload)) || rec.HasItem(row.dataItem) != (row.err == nil) {
+			t.Errorf("FAIL: %s - item = %v, error = %v; Expected: %x, %v", row.TestCaseName, item, err, row.payload, row.err)
+		} else {
+			t.Logf("SUCCESS: %s - payload = %x, error = %v", row.TestCaseName, row.payload, err)
+		}
+	}
+}
+
+func TestRecord_Item(t *testing.T) {
+	// Arrange
+	data, _ := util.HexStringToByte("f6083602429b7110940028200094008000")
+	rec := NewRecord()
+	_, _ = rec.Decode(data, uap.Cat034V127)
+
+	// Act
+	item, err := rec.Item(3)
+	_, errAbsent := rec.Item(5)
+
+	// Assert
+	if err != nil || item.Meta.DataItem != "I034/030" || !rec.Has(3) {
+		t.Errorf("FAIL: item = %v, error = %v; Expected: I034/030, %v", item, err, nil)
+	}
+	if errAbsent != ErrItemNotFound || rec.Has(5) {
+		t.Errorf("FAIL: error = %v; Expected: %v", errAbsent, ErrItemNotFound)
+	} else {
+		t.Logf("SUCCESS: item = %v, absent: %v", item.String(), errAbsent)
+	}
+}
+
+func TestRecord_TypedGetters(t *testing.T) {
+	// setup
+	data, _ := util.HexStringToByte(cat062Len1351)
+	db, _, _ := NewDecoder().DecodeDataBlock(data)
+	rec := db.Records[0]
+	type dataTest struct {
+		TestCaseName string
+		get          func() error
+		err          error
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "Fixed",
+			get: func() error {
+				_, err := rec.Fixed("I062/010")
+				return err
+			},
+			err: nil,
+		},
+		{
+			TestCaseName: "Fixed of a compound subfield",
+			get: func() error {
+				_, err := rec.Fixed("I062/380/ID")
+				return err
+			},
+			err: nil,
+		},
+		{
+			TestCaseName: "Extended",
+			get: func() error {
+				_, err := rec.Extended("I062/080")
+				return err
+			},
+			err: nil,
+		},
+		{
+			TestCaseName: "Compound",
+			get: func() error {
+				cp, err := rec.Compound("I062/290")
+				if err == nil && len(cp.Secondary) != 8 {
+					return ErrItemType
+				}
+				return err
+			},
+			err: nil,
+		},
+		{
+			TestCaseName: "Extended of a Fixed item",
+			get: func() error {
+				_, err := rec.Extended("I062/010")
+				return err
+			},
+			err: ErrItemType,
+		},
+		{
+			TestCaseName: "Fixed of a Compound item",
+			get: func() error {
+				_, err := rec.Fixed("I062/380")
+				return err
+			},
+			err: ErrItemType,
+		},
+		{
+			TestCaseName: "Explicit absent",
+			get: func() error {
+				_, err := rec.Explicit("I062/390")
+				return err
+			},
+			err: ErrItemNotFound,
+		},
+		{
+			TestCaseName: "Repetitive absent",
+			get: func() error {
+				_, err := rec.Repetitive("I062/510")
+				return err
+			},
+			err: ErrItemNotFound,
+		},
+	}
+
+	for _, row := range dataSet {
+		// Act
+		err := row.get()
+
+		// Assert
+		if err != row.err {
+			t.Errorf("FAIL: %s - error = %v; Expected: %v", row.TestCaseName, err, row.err)
+		} else {
+			t.Logf("SUCCESS: %s - error = %v", row.TestCaseName, err)
+		}
+	}
+}
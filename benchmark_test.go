@@ -70,6 +70,22 @@ func BenchmarkRecordDecode_Len73(b *testing.B) {
 		b)
 }
 
+// benchmark one cat048 record with a compiled UAP
+func BenchmarkRecordDecodePlan_Len55(b *testing.B) {
+	data, _ := util.HexStringToByte("fff702 0836 429b52 a0 94c70181 0913 02d0 6002b7 490d01 38a178cf4220 02e79a5d27a00c0060a3280030a4000040 063a 0743ce5b 40 20f5")
+	plan := Compile(uap.Cat048V127)
+	for n := 0; n < b.N; n++ {
+		rec := new(Record)
+		unRead, err := rec.DecodePlan(data, plan)
+		if err != nil {
+			b.Errorf("FAIL: error = %v; Expected: %v", err, nil)
+		}
+		if unRead != 0 {
+			b.Errorf("FAIL: unRead = %v; Expected: %v", unRead, 0)
+		}
+	}
+}
+
 func benchmarkDataBlockDecode(input string, b *testing.B) {
 	data, _ := util.HexStringToByte(input)
 	for n := 0; n < b.N; n++ {
@@ -92,6 +108,49 @@ func BenchmarkDataBlock_Len280(b *testing.B) {
 		b)
 }
 
+// benchmark one cat062 datablock (11 records)
+// cat062Len1351 is a cat062 datablock
+const cat062Len1351 = "3e0547bf5ffd0304090001532100008e6f3e0017d0961247f10b7086fed3019a0fc8e301010c87304a04e072c34820e300820800eb003104b2190301487fa0ff0614ffffffffffff0493110101c006061414141400e0045b00e00182dc622931a410a800e00fc84010e001622b05010d01622902fea60177bf5ffd0304090001532100008f45be000478e9036aa20b78f8fdbc023c0f55e301010c40123f0815f5cf1820dee002d0010f005f002c190301087fa02a0707ffffffffffff0893110101c0070707070707051f13c5051bfdd4dc085066b0f616051f0f55a02aa0070814050221060b0500b108360502ea0813050761060a05056808090504340850050236fdb10230bf5ffd0304090001532100008ea9d100149a720fcb720b75af033a014d0baae301010c780de50c54f7c39e202bc003c0012b00560336190301087fa00e0606ffffffffffff0493110101c0060606060606039715f00399013e98060a03970baa1fe00408120501f6080a05065f06030701f4060a0503b10162290203180199bf5ffd0304090001532100008f5d7f0000d79400a4960b8ca4026cfd4b0ec7e301010c4009c70815f4e0356064c00578011d00570321190301487fa0ff0808ffffffffffff0493110101c0080808080808040b16b9040d00bddc08097dd1e0a6040b0ec7a025a006060b0506e5081305075c08140506c3083605038408500507e6080905051c026ffd4fbf5ffd0304090001532100008e74b40018ecd31320cf0b8f89fe8bff780684e301010c4bcdee4d84f7cc3820af0000c800bd002601e2190301487fa0ff1313ffffffffffff0893110101c01313131313130067023f0065ff4d98622b006726840ca001622b05007efe9cff4dbf5ffd0304090001532100008e9f9d00172dfb11c53c0b9c93fd09014d064be301010c4bc846407532dd7820d3600550010b006001e5190301487fa0ff060bffffffffffff0093110101c006060b0b0b0b0550149f05500000dc6229330c09100550064b4015e002622b050901080a05018701622902fce900fcbf4ffd0304090001532100008e66d600187dc812cdb00b7555ff07ff960991006f990301587fa0ff19ffffffffffffff28910101010019190030009a002eff71986229003009910620ff13ff7abf5ffd0304090001532100008ef1c50010032c0c41460b7f70037efec10200e301010c4ca4f84994b3e774a04a400528011b00590359190301087fa0050404ffffffffffff4493110101c0040404040404044d1726044f013b980814044d020033e00808120505a5080a0500bb08360504bc060b0503190603070760060a07071d0809050527081405044a016229020393ff01bf5ffd0304090001532100008ef000001110340d0f160b8d0703dbffaa0200e301010c4ca8af4994b8e4d320404005280118005f0277190301087fa00c0404ffffffffffff0493110101c004040404040404d117fe04d200e698081404d102002ee00708120506ce080a0500ce08360504d106030706dc060a0504f908090502bb08140500fe0162290203dffff7bf5ffd0304090001532100008f19fe000bb36808f2860b7977ff4d00190400c101010044d9c93cf58e2608200146990301087fa0313131ffffffffffff0091010101003131004800a000480000980603004804000620ff4b000fbf5ffd0304090001532100008ef943001611ec10e10c0bf096041afec70ab1e301010c8013c2594270c78820466085c801130069002e190301087fa00f0606ffffffffffff0093110101c006060606060605c7190005c70000dc62293988fcf005c70ab14024e005622b05050d08120506ad080a0507160603070756060a0504e5016229020435ff30"
+
+func BenchmarkDataBlock_CAT062_Len1351(b *testing.B) {
+	benchmarkDataBlockDecode(
+		cat062Len1351,
+		b)
+}
+
+// benchmark one cat062 datablock decoded without copy and with the records reused
+func BenchmarkDataBlock_CAT062_Len1351_Aliasing(b *testing.B) {
+	benchmarkDataBlockDecodeAliasing(
+		cat062Len1351,
+		b)
+}
+
+// benchmark one cat048 datablock decoded without copy and with the records reused
+func BenchmarkDataBlock_Len280_Aliasing(b *testing.B) {
+	benchmarkDataBlockDecodeAliasing(
+		"300118fff7020836429b52a094c70181091302d06002b7490d0138a178cf422002e79a5d27a00c0060a3280030a4000040063a0743ce5b4020f5fff7020836429b54e000bc020901a2005c7802e800263946e50464b1cb6ca0029ea9491062a4546093880032d4000040059602f639590220f5fff7020836429b58a0909703ff026405a26002bb4066740815f6e795e002e56a0530ffdff860b0d80032fc00004003cf0810c9ef4020fdfff7020836429b56a0775d03700ec205786002be4060910815f9c363a002a49a0f30bfffff60c4600030a4000040057207674a004020fdfff7020836429b55a0468c029804b105786002c57101124d6070d3282002adfa3333a0140060c4600030a4000040026e07d75fc04020f5",
+		b)
+}
+
+func benchmarkDataBlockDecodeAliasing(input string, b *testing.B) {
+	benchmarkDecoderDataBlock(input, NewDecoder(WithAliasing()), b)
+}
+
+func benchmarkDecoderDataBlock(input string, d *Decoder, b *testing.B) {
+	data, _ := util.HexStringToByte(input)
+	for n := 0; n < b.N; n++ {
+		dataB, unRead, err := d.DecodeDataBlock(data)
+
+		if err != nil {
+			b.Errorf("FAIL: error = %v; Expected: %v", err, nil)
+		}
+		if unRead != 0 {
+			b.Errorf("FAIL: unRead = %v; Expected: %v", unRead, 0)
+		}
+		dataB.Release()
+	}
+}
+
 func benchmarkWrapperDataBlockDecode(input string, b *testing.B) {
 	data, _ := util.HexStringToByte(input)
 	for n := 0; n < b.N; n++ {
@@ -112,3 +171,47 @@ func BenchmarkWapperDataBlock_Len768(b *testing.B) {
 		"300180fff70208364eadc8a2a44411850fff07a86002c5382fdb4cd4f240e8200100000000000000e10004000cd3bd4022a0fff70208364eadc8a2544411940fff07946001cb382fbb4cd4f140e8200100000000000000e10005001d32884022a0fff70208364eadd0a03d09158f045605c86002c94853d4512071d3706002c919ff3160140060c8480030a800004002ea07e392944022f5ffd70208364eadcfa0accc153d058304386002b744f1a20811b2e3282006810856feb7402aa0fff70208364eadc7a07420113c045a06016002c24853d2512073cca82002c839ef3161542960d0180030a800004005a007da911b4022f5fff70208364eadcca07fff1371056305ef6002bf43ec3ec931d31e082002ea99f331201c0160ca3c0130a800004003e30804d2f74022f5ff1608364eadd26007ba15b80e000038f84c07d43d4600cb0173530e00fff70208364eadc5a03e95104105e606406002c84ca97c4994b710582002eff9d13020240060ce267130a800004002ae07c3dfc64022fd",
 		b)
 }
+
+func BenchmarkWapperDataBlock_Len768_Aliasing(b *testing.B) {
+	data, _ := util.HexStringToByte("300180fff70208364eadc8a2a44411850fff07a86002c5382fdb4cd4f240e8200100000000000000e10004000cd3bd4022a0fff70208364eadc8a2544411940fff07946001cb382fbb4cd4f140e8200100000000000000e10005001d32884022a0fff70208364eadd0a03d09158f045605c86002c94853d4512071d3706002c919ff3160140060c8480030a800004002ea07e392944022f5ffd70208364eadcfa0accc153d058304386002b744f1a20811b2e3282006810856feb7402aa0fff70208364eadc7a07420113c045a06016002c24853d2512073cca82002c839ef3161542960d0180030a800004005a007da911b4022f5fff70208364eadcca07fff1371056305ef6002bf43ec3ec931d31e082002ea99f331201c0160ca3c0130a800004003e30804d2f74022f5ff1608364eadd26007ba15b80e000038f84c07d43d4600cb0173530e00fff70208364eadc5a03e95104105e606406002c84ca97c4994b710582002eff9d13020240060ce267130a800004002ae07c3dfc64022fd")
+	d := NewDecoder(WithAliasing())
+	for n := 0; n < b.N; n++ {
+		w, unRead, err := d.DecodeWrapper(data)
+
+		if err != nil {
+			b.Errorf("FAIL: error = %v; Expected: %v", err, nil)
+		}
+		if unRead != 0 {
+			b.Errorf("FAIL: unRead = %v; Expected: %v", unRead, 0)
+		}
+		w.Release()
+	}
+}
+
+// benchmark one cat048 datablock located without decoding the items, only I048/140 (Time of Day) is decoded
+func BenchmarkLazyDataBlock_Len280_Aliasing(b *testing.B) {
+	data, _ := util.HexStringToByte("300118fff7020836429b52a094c70181091302d06002b7490d0138a178cf422002e79a5d27a00c0060a3280030a4000040063a0743ce5b4020f5fff7020836429b54e000bc020901a2005c7802e800263946e50464b1cb6ca0029ea9491062a4546093880032d4000040059602f639590220f5fff7020836429b58a0909703ff026405a26002bb4066740815f6e795e002e56a0530ffdff860b0d80032fc00004003cf0810c9ef4020fdfff7020836429b56a0775d03700ec205786002be4060910815f9c363a002a49a0f30bfffff60c4600030a4000040057207674a004020fdfff7020836429b55a0468c029804b105786002c57101124d6070d3282002adfa3333a0140060c4600030a4000040026e07d75fc04020f5")
+	d := NewDecoder(WithAliasing())
+	for n := 0; n < b.N; n++ {
+		db, unRead, err := d.DecodeLazy(data)
+		if err != nil {
+			b.Errorf("FAIL: error = %v; Expected: %v", err, nil)
+		}
+		if unRead != 0 {
+			b.Errorf("FAIL: unRead = %v; Expected: %v", unRead, 0)
+		}
+		for _, rec := range db.Records {
+			if _, found := rec.Data(2); !found {
+				b.Errorf("FAIL: I048/140 not found")
+			}
+		}
+	}
+}
+
+// benchmark one cat062 datablock decoded without copy, only I062/105 and I062/136 are decoded
+func BenchmarkDataBlock_CAT062_Len1351_Selection(b *testing.B) {
+	benchmarkDecoderDataBlock(
+		cat062Len1351,
+		NewDecoder(WithAliasing(), WithSelection(62, "I062/105", "I062/136")),
+		b)
+}
@@ -0,0 +1,68 @@
+// Command uapgen generates the Go source of a User Application Profile (uap.StandardUAP) from a specification file
+// in the asterix-specs JSON format or in the asterix XML format (see package uap/loader).
+// It generates optionally the model structs of the items with a bit-level definition,
+// filled by the values of generic.DecodeRecord.
+//
+// Usage:
+//
+//	uapgen -spec cat062_1.19.json -var Cat062V119 -o cat062.go [-pkg uap] [-model ../transform/cat062_model.go]
+//
+// It is run by go generate in the uap package (see uap/generate.go), e.g.:
+//
+//	//go:generate go run ../cmd/uapgen -spec specs/cat034_1.27.json -var Cat034V127 -o cat034.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mokhtarimokhtar/goasterix/uap/loader"
+)
+
+func main() {
+	spec := flag.String("spec", "", "specification file (asterix-specs JSON or asterix XML)")
+	varName := flag.String("var", "", "name of the generated profile variable, e.g. Cat062V119")
+	output := flag.String("o", "", "generated profile file, standard output if empty")
+	pkg := flag.String("pkg", "uap", "package of the generated profile")
+	model := flag.String("model", "", "generated model file, no model if empty")
+	modelPkg := flag.String("model-pkg", "transform", "package of the generated model")
+	flag.Parse()
+
+	if *spec == "" || *varName == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(*spec, *varName, *output, *pkg, *model, *modelPkg); err != nil {
+		fmt.Fprintln(os.Stderr, "uapgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(spec, varName, output, pkg, model, modelPkg string) error {
+	stdUAP, err := loader.LoadFile(spec)
+	if err != nil {
+		return err
+	}
+	source := filepath.Base(spec)
+
+	src, err := generateProfile(stdUAP, varName, pkg, source)
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		_, err = os.Stdout.Write(src)
+	} else {
+		err = os.WriteFile(output, src, 0644)
+	}
+	if err != nil || model == "" {
+		return err
+	}
+
+	src, err = generateModel(stdUAP, varName, modelPkg, source)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(model, src, 0644)
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/mokhtarimokhtar/goasterix/uap"
+)
+
+// modelStruct is a generated struct of the values of a data field.
+type modelStruct struct {
+	name   string
+	fields []modelField
+}
+
+// modelField is a field of a generated struct: a subfield value or a nested data field.
+type modelField struct {
+	name     string
+	goType   string
+	tag      string
+	key      string // subfield name or DataItem of the nested data field
+	nested   *modelStruct
+	repeated bool
+}
+
+// generateModel returns the formatted Go source of the model structs of stdUAP in the package pkg.
+// The model contains the items with a bit-level definition, its method Write fills it
+// with the values of generic.DecodeRecord.
+func generateModel(stdUAP uap.StandardUAP, varName string, pkg string, source string) ([]byte, error) {
+	fields := append([]uap.DataField{}, stdUAP.Items...)
+	if c := stdUAP.Condition; c != nil {
+		keys := make([]int, 0, len(c.Variants))
+		for key := range c.Variants {
+			keys = append(keys, int(key))
+		}
+		sort.Ints(keys)
+		for _, key := range keys {
+			fields = append(fields, c.Variants[uint8(key)]...)
+		}
+	}
+
+	var structs []*modelStruct
+	top := &modelStruct{name: varName + "Model"}
+	seen := make(map[string]bool)
+	for _, field := range fields {
+		if field.DataItem == "" || seen[field.DataItem] {
+			continue
+		}
+		seen[field.DataItem] = true
+		s := dataFieldStruct(field, varName+ident(field.DataItem), &structs)
+		if s == nil {
+			continue
+		}
+		top.fields = append(top.fields, modelField{
+			name:     uniqueName(top.fields, ident(field.DataItem)),
+			tag:      field.DataItem,
+			key:      field.DataItem,
+			nested:   s,
+			repeated: field.Type == uap.Repetitive,
+		})
+	}
+	if len(top.fields) == 0 {
+		return nil, fmt.Errorf("%s: no item with a bit-level definition", stdUAP.Name)
+	}
+
+	g := generator{}
+	g.printf("// Code generated by uapgen from %s; DO NOT EDIT.\n\n", source)
+	g.printf("package %s\n\n", pkg)
+	g.printf("import \"github.com/mokhtarimokhtar/goasterix/generic\"\n\n")
+
+	g.printf("// %s contains the values of a record decoded with the profile %s.\n", top.name, varName)
+	g.structType(top)
+	g.printf("// Write fills the model with the values of a record returned by generic.DecodeRecord.\n")
+	g.printf("func (m *%s) Write(fields []generic.Field) {\n", top.name)
+	g.nestedWrite("m", "fields", top)
+	g.printf("}\n\n")
+
+	for _, s := range structs {
+		g.structType(s)
+		g.printf("func (m *%s) write(f generic.Field) {\n", s.name)
+		hasValues, hasNested := false, false
+		for _, mf := range s.fields {
+			hasValues = hasValues || mf.nested == nil
+			hasNested = hasNested || mf.nested != nil
+		}
+		if hasValues {
+			g.printf("for _, v := range f.Values {\nswitch v.Name {\n")
+			for _, mf := range s.fields {
+				if mf.nested == nil {
+					g.printf("case %q:\nm.%s, _ = v.Value.(%s)\n", mf.key, mf.name, mf.goType)
+				}
+			}
+			g.printf("}\n}\n")
+		}
+		if hasNested {
+			g.nestedWrite("m", "f.Items", s)
+		}
+		g.printf("}\n\n")
+	}
+	return format.Source(g.buf.Bytes())
+}
+
+func (g *generator) structType(s *modelStruct) {
+	g.printf("type %s struct {\n", s.name)
+	for _, mf := range s.fields {
+		switch {
+		case mf.nested != nil && mf.repeated:
+			g.printf("%s []%s `json:\"%s,omitempty\"`\n", mf.name, mf.nested.name, mf.tag)
+		case mf.nested != nil:
+			g.printf("%s *%s `json:\"%s,omitempty\"`\n", mf.name, mf.nested.name, mf.tag)
+		default:
+			g.printf("%s %s `json:\"%s\"`\n", mf.name, mf.goType, mf.tag)
+		}
+	}
+	g.printf("}\n\n")
+}
+
+// nestedWrite writes the code filling the nested fields of s (receiver m) with a list of generic.Field.
+func (g *generator) nestedWrite(m string, list string, s *modelStruct) {
+	g.printf("for _, item := range %s {\nswitch item.DataItem {\n", list)
+	for _, mf := range s.fields {
+		if mf.nested == nil {
+			continue
+		}
+		g.printf("case %q:\n", mf.key)
+		if mf.repeated {
+			g.printf("%s.%s = nil\n", m, mf.name)
+			g.printf("for _, rep := range item.Items {\ntmp := %s{}\ntmp.write(rep)\n", mf.nested.name)
+			g.printf("%s.%s = append(%s.%s, tmp)\n}\n", m, mf.name, m, mf.name)
+		} else {
+			g.printf("%s.%s = &%s{}\n%s.%s.write(item)\n", m, mf.name, mf.nested.name, m, mf.name)
+		}
+	}
+	g.printf("}\n}\n")
+}
+
+// dataFieldStruct returns the struct of the values of a data field, nil if it has no bit-level definition.
+// The structs of the nested data fields are appended to structs.
+func dataFieldStruct(field uap.DataField, name string, structs *[]*modelStruct) *modelStruct {
+	s := &modelStruct{name: name}
+	for _, sf := range field.SubFields {
+		if hasKey(s.fields, sf.Name) {
+			continue
+		}
+		s.fields = append(s.fields, modelField{
+			name:   uniqueName(s.fields, ident(sf.Name)),
+			goType: valueType(sf),
+			tag:    strings.ToLower(sf.Name),
+			key:    sf.Name,
+		})
+	}
+	for _, sub := range field.Compound {
+		if sub.DataItem == "" || hasKey(s.fields, sub.DataItem) {
+			continue
+		}
+		nested := dataFieldStruct(sub, name+ident(sub.DataItem), structs)
+		if nested == nil {
+			continue
+		}
+		s.fields = append(s.fields, modelField{
+			name:     uniqueName(s.fields, ident(sub.DataItem)),
+			tag:      strings.ToLower(sub.DataItem),
+			key:      sub.DataItem,
+			nested:   nested,
+			repeated: sub.Type == uap.Repetitive,
+		})
+	}
+	if len(s.fields) == 0 {
+		return nil
+	}
+	*structs = append(*structs, s)
+	return s
+}
+
+// valueType returns the Go type of the value of a subfield decoded by the generic package.
+func valueType(sf uap.SubField) string {
+	switch {
+	case sf.Format != uap.Numeric || sf.Enum != nil:
+		return "string"
+	case sf.Scale != 0:
+		return "float64"
+	case sf.Signed:
+		return "int64"
+	}
+	return "uint64"
+}
+
+func hasKey(fields []modelField, key string) bool {
+	for _, mf := range fields {
+		if mf.key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueName returns name, suffixed by a number if a field has already this name.
+func uniqueName(fields []modelField, name string) string {
+	tmp := name
+	for i := 2; ; i++ {
+		used := false
+		for _, mf := range fields {
+			used = used || mf.name == tmp
+		}
+		if !used {
+			return tmp
+		}
+		tmp = fmt.Sprintf("%s%d", name, i)
+	}
+}
+
+// ident returns an exported Go identifier from a name of the specification, e.g. "I048/010" => "I048010".
+func ident(name string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	id := sb.String()
+	if id == "" || !unicode.IsLetter(rune(id[0])) {
+		id = "F" + id
+	}
+	return id
+}
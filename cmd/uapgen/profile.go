@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+
+	"github.com/mokhtarimokhtar/goasterix/uap"
+)
+
+var typeNames = map[uap.TypeField]string{
+	uap.Fixed:      "Fixed",
+	uap.Extended:   "Extended",
+	uap.Compound:   "Compound",
+	uap.Repetitive: "Repetitive",
+	uap.Explicit:   "Explicit",
+	uap.SP:         "SP",
+	uap.RE:         "RE",
+	uap.RFS:        "RFS",
+	uap.Spare:      "Spare",
+}
+
+var formatNames = map[uap.Format]string{
+	uap.Octal: "Octal",
+	uap.Hex:   "Hex",
+	uap.ICAO6: "ICAO6",
+	uap.ASCII: "ASCII",
+}
+
+// generateProfile returns the formatted Go source declaring the variable varName of stdUAP in the package pkg,
+// in the layout of the handwritten profiles of the uap package.
+func generateProfile(stdUAP uap.StandardUAP, varName string, pkg string, source string) ([]byte, error) {
+	g := generator{}
+	if pkg != "uap" {
+		g.q = "uap."
+	}
+	version := strconv.FormatFloat(stdUAP.Version, 'f', -1, 64)
+
+	g.printf("// Code generated by uapgen from %s; DO NOT EDIT.\n\n", source)
+	g.printf("package %s\n\n", pkg)
+	if g.q != "" {
+		g.printf("import \"github.com/mokhtarimokhtar/goasterix/uap\"\n\n")
+	}
+	g.printf("// %s User Application Profile\n// version %s\n", varName, version)
+	g.printf("var %s = %sStandardUAP{\n", varName, g.q)
+	g.printf("Name: %q,\n", stdUAP.Name)
+	g.printf("Category: %d,\n", stdUAP.Category)
+	g.printf("Version: %s,\n", version)
+	g.fields("Items", stdUAP.Items)
+	if c := stdUAP.Condition; c != nil {
+		g.printf("Condition: &%sCondition{\n", g.q)
+		g.printf("FRN: %d,\nOctet: %d,\nMask: 0x%02x,\n", c.FRN, c.Octet, c.Mask)
+		g.printf("Variants: map[uint8][]%sDataField{\n", g.q)
+		keys := make([]int, 0, len(c.Variants))
+		for key := range c.Variants {
+			keys = append(keys, int(key))
+		}
+		sort.Ints(keys)
+		for _, key := range keys {
+			g.fields(fmt.Sprintf("0x%02x", key), c.Variants[uint8(key)])
+		}
+		g.printf("},\n},\n")
+	}
+	if len(stdUAP.ReservedExpansion) != 0 {
+		g.fields("ReservedExpansion", stdUAP.ReservedExpansion)
+	}
+	if len(stdUAP.SpecialPurpose) != 0 {
+		g.fields("SpecialPurpose", stdUAP.SpecialPurpose)
+	}
+	g.printf("}\n")
+
+	return format.Source(g.buf.Bytes())
+}
+
+// generator writes Go source, q is the qualifier of the identifiers of the uap package.
+type generator struct {
+	buf bytes.Buffer
+	q   string
+}
+
+func (g *generator) printf(f string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, f, args...)
+}
+
+// fields writes a list of data fields as the value of key (a struct field or a map key).
+func (g *generator) fields(key string, fields []uap.DataField) {
+	g.printf("%s: []%sDataField{\n", key, g.q)
+	for _, field := range fields {
+		g.field(field)
+	}
+	g.printf("},\n")
+}
+
+func (g *generator) field(field uap.DataField) {
+	g.printf("{\n")
+	g.printf("FRN: %d,\n", field.FRN)
+	if field.DataItem != "" {
+		g.printf("DataItem: %q,\n", field.DataItem)
+	}
+	if field.Description != "" {
+		g.printf("Description: %q,\n", field.Description)
+	}
+	g.printf("Type: %s%s,\n", g.q, typeNames[field.Type])
+
+	switch field.Type {
+	case uap.Fixed:
+		g.printf("Fixed: %sFixedField{\nSize: %d,\n},\n", g.q, field.Fixed.Size)
+	case uap.Extended:
+		g.printf("Extended: %sExtendedField{\nPrimarySize: %d,\nSecondarySize: %d,\n},\n",
+			g.q, field.Extended.PrimarySize, field.Extended.SecondarySize)
+	case uap.Repetitive:
+		g.printf("Repetitive: %sRepetitiveField{\nSubItemSize: %d,\n},\n", g.q, field.Repetitive.SubItemSize)
+	case uap.Explicit:
+		if field.Explicit.Size != 0 {
+			g.printf("Explicit: %sExplicitField{\nSize: %d,\n},\n", g.q, field.Explicit.Size)
+		}
+	}
+	if len(field.Compound) != 0 {
+		g.fields("Compound", field.Compound)
+	}
+	if len(field.SubFields) != 0 {
+		g.printf("SubFields: []%sSubField{\n", g.q)
+		for _, sf := range field.SubFields {
+			g.subField(sf)
+		}
+		g.printf("},\n")
+	}
+	g.printf("},\n")
+}
+
+func (g *generator) subField(sf uap.SubField) {
+	g.printf("{Name: %q, From: %d, To: %d", sf.Name, sf.From, sf.To)
+	if sf.Part != 0 {
+		g.printf(", Part: %d", sf.Part)
+	}
+	if sf.Signed {
+		g.printf(", Signed: true")
+	}
+	if sf.Scale != 0 {
+		g.printf(", Scale: %s", strconv.FormatFloat(sf.Scale, 'g', -1, 64))
+	}
+	if sf.Unit != "" {
+		g.printf(", Unit: %q", sf.Unit)
+	}
+	if name, found := formatNames[sf.Format]; found {
+		g.printf(", Format: %s%s", g.q, name)
+	}
+	if len(sf.Enum) != 0 {
+		keys := make([]uint64, 0, len(sf.Enum))
+		for key := range sf.Enum {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+		g.printf(", Enum: map[uint64]string{\n")
+		for _, key := range keys {
+			g.printf("%d: %q,\n", key, sf.Enum[key])
+		}
+		g.printf("}")
+	}
+	g.printf("},\n")
+}
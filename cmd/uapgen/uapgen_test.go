@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mokhtarimokhtar/goasterix/uap"
+	"github.com/mokhtarimokhtar/goasterix/uap/loader"
+)
+
+func TestGenerateProfile(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		stdUAP       uap.StandardUAP
+		pkg          string
+		contains     []string
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "uap package",
+			stdUAP:       uap.Cat048V127,
+			pkg:          "uap",
+			contains: []string{
+				"var Cat048Gen = StandardUAP{",
+				`DataItem:    "I048/010",`,
+				`{Name: "FL", From: 14, To: 1, Signed: true, Scale: 0.25, Unit: "FL"},`,
+				`{Name: "IDENT", From: 48, To: 1, Format: ICAO6},`,
+			},
+		},
+		{
+			TestCaseName: "other package with conditional UAP",
+			stdUAP:       uap.Cat4Test,
+			pkg:          "profiles",
+			contains: []string{
+				`import "github.com/mokhtarimokhtar/goasterix/uap"`,
+				"var Cat048Gen = uap.StandardUAP{",
+				"Type:        uap.Compound,",
+				"Condition: &uap.Condition{",
+				"0x80: []uap.DataField{",
+			},
+		},
+	}
+
+	for _, row := range dataSet {
+		// Act
+		src, err := generateProfile(row.stdUAP, "Cat048Gen", row.pkg, "test")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("FAIL: %s - error = %v; Expected: %v", row.TestCaseName, err, nil)
+		}
+		if _, err := parser.ParseFile(token.NewFileSet(), "", src, 0); err != nil {
+			t.Errorf("FAIL: %s - parse error = %v; Expected: %v", row.TestCaseName, err, nil)
+		}
+		for _, s := range row.contains {
+			if !strings.Contains(string(src), s) {
+				t.Errorf("FAIL: %s - source does not contain %s", row.TestCaseName, s)
+			} else {
+				t.Logf("SUCCESS: source contains %s", s)
+			}
+		}
+	}
+}
+
+func TestGenerateModel(t *testing.T) {
+	// Arrange
+	stdUAP, err := loader.LoadFile("../../uap/loader/testdata/cat048.xml")
+	if err != nil {
+		t.Fatalf("FAIL: error = %v; Expected: %v", err, nil)
+	}
+	contains := []string{
+		"package transform",
+		"type Cat048GenModel struct {",
+		"func (m *Cat048GenModel) Write(fields []generic.Field) {",
+		"I048010 *Cat048GenI048010",
+		"RHO float64 `json:\"rho\"`",
+		"SAM *Cat048GenI048130SAM `json:\"sam,omitempty\"`",
+		"m.TYP, _ = v.Value.(string)",
+	}
+
+	// Act
+	src, err := generateModel(stdUAP, "Cat048Gen", "transform", "cat048.xml")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("FAIL: error = %v; Expected: %v", err, nil)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "", src, 0); err != nil {
+		t.Errorf("FAIL: parse error = %v; Expected: %v", err, nil)
+	}
+	text := strings.Join(strings.Fields(string(src)), " ") // ignore the alignment of gofmt
+	for _, s := range contains {
+		if !strings.Contains(text, s) {
+			t.Errorf("FAIL: source does not contain %s", s)
+		} else {
+			t.Logf("SUCCESS: source contains %s", s)
+		}
+	}
+}
+
+func TestGeneratedProfiles(t *testing.T) {
+	// setup
+	// the go:generate directives of the uap package, run in its directory
+	const dir = "../../uap"
+	const prefix = "//go:generate go run ../cmd/uapgen "
+	directives, err := os.ReadFile(filepath.Join(dir, "generate.go"))
+	if err != nil {
+		t.Fatalf("FAIL: error = %v; Expected: %v", err, nil)
+	}
+	n := 0
+
+	for _, line := range strings.Split(string(directives), "\n") {
+		if !strings.HasPrefix(line, prefix) {
+			continue
+		}
+		n++
+		fs := flag.NewFlagSet("uapgen", flag.ContinueOnError)
+		spec := fs.String("spec", "", "")
+		varName := fs.String("var", "", "")
+		output := fs.String("o", "", "")
+		pkg := fs.String("pkg", "uap", "")
+		if err := fs.Parse(strings.Fields(line[len(prefix):])); err != nil {
+			t.Fatalf("FAIL: %s - error = %v; Expected: %v", line, err, nil)
+		}
+
+		// Arrange
+		stdUAP, err := loader.LoadFile(filepath.Join(dir, *spec))
+		if err != nil {
+			t.Fatalf("FAIL: %s - error = %v; Expected: %v", *spec, err, nil)
+		}
+		committed, err := os.ReadFile(filepath.Join(dir, *output))
+		if err != nil {
+			t.Fatalf("FAIL: %s - error = %v; Expected: %v", *output, err, nil)
+		}
+
+		// Act
+		src, err := generateProfile(stdUAP, *varName, *pkg, filepath.Base(*spec))
+
+		// Assert
+		if err != nil {
+			t.Fatalf("FAIL: %s - error = %v; Expected: %v", *spec, err, nil)
+		}
+		if !bytes.Equal(src, committed) {
+			t.Errorf("FAIL: %s is not up to date with %s, run go generate ./uap", *output, *spec)
+		} else {
+			t.Logf("SUCCESS: %s is up to date with %s", *output, *spec)
+		}
+	}
+	if n == 0 {
+		t.Errorf("FAIL: no go:generate directive in %s", filepath.Join(dir, "generate.go"))
+	}
+}
+
+func TestIdent(t *testing.T) {
+	// setup
+	dataSet := map[string]string{
+		"I048/010": "I048010",
+		"FOE/FRI":  "FOEFRI",
+		"ToD":      "ToD",
+		"140":      "F140",
+		"mode 3a":  "Mode3a",
+	}
+
+	for input, output := range dataSet {
+		// Act
+		id := ident(input)
+
+		// Assert
+		if id != output {
+			t.Errorf("FAIL: ident(%s) = %s; Expected: %s", input, id, output)
+		} else {
+			t.Logf("SUCCESS: ident(%s) = %s; Expected: %s", input, id, output)
+		}
+	}
+}
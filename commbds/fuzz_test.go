@@ -0,0 +1,19 @@
+package commbds
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func FuzzBdsDecode(f *testing.F) {
+	for _, seed := range []string{"ffffffffffffff60", "ffffffffffffff40", "ffffffffffffff50", "0000000000000000"} {
+		data, _ := hex.DecodeString(seed)
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, input []byte) {
+		var data [8]byte
+		copy(data[:], input)
+		ds := new(Bds)
+		_ = ds.Decode(data)
+	})
+}
@@ -0,0 +1,401 @@
+package goasterix
+
+import (
+	"io"
+	"math/bits"
+	"sync"
+
+	"github.com/mokhtarimokhtar/goasterix/uap"
+)
+
+// cursor reads the data fields of a record without copy: the slices returned alias its data.
+type cursor struct {
+	data []byte
+	pos  int
+}
+
+// len returns the number of bytes unread.
+func (c *cursor) len() int {
+	return len(c.data) - c.pos
+}
+
+// next returns the n next bytes. Like io.ReadFull, it returns io.EOF if no byte is left and io.ErrUnexpectedEOF
+// if fewer than n bytes are left, the cursor is then at the end of data.
+func (c *cursor) next(n int) ([]byte, error) {
+	if n > c.len() {
+		err := io.ErrUnexpectedEOF
+		if c.len() == 0 {
+			err = io.EOF
+		}
+		c.pos = len(c.data)
+		return nil, err
+	}
+	b := c.data[c.pos : c.pos+n : c.pos+n]
+	c.pos += n
+	return b, nil
+}
+
+// byte returns the next byte or io.EOF.
+func (c *cursor) byte() (uint8, error) {
+	if c.len() == 0 {
+		return 0, io.EOF
+	}
+	c.pos++
+	return c.data[c.pos-1], nil
+}
+
+// fspec returns the next FSPEC (or primary subfield of a compound item): the bytes until the first FX bit unset.
+func (c *cursor) fspec() ([]byte, error) {
+	for i := c.pos; i < len(c.data); i++ {
+		if c.data[i]&0x01 == 0 {
+			return c.next(i + 1 - c.pos)
+		}
+	}
+	c.pos = len(c.data)
+	return nil, io.EOF
+}
+
+// nextFRN returns the first FRN greater than frn set in fspec, 0 if none.
+// The bits beyond the FRN 255 are ignored, like FspecIndex.
+func nextFRN(fspec []byte, frn uint8) uint8 {
+	n := int(frn)
+	for j := n / 7; j < len(fspec); j++ {
+		octet := fspec[j] & 0xfe     // without FX
+		octet &= 0xff >> uint(n-7*j) // without the FRNs until frn
+		if octet != 0 {
+			next := 7*j + bits.LeadingZeros8(octet) + 1
+			if next > 0xff {
+				return 0
+			}
+			return uint8(next)
+		}
+		n = 7 * (j + 1)
+	}
+	return 0
+}
+
+// recordPool contains the records released by DataBlock.Release.
+var recordPool = sync.Pool{
+	New: func() interface{} {
+		return NewRecord()
+	},
+}
+
+// getRecord returns a record of the pool.
+func getRecord() *Record {
+	return recordPool.Get().(*Record)
+}
+
+// release resets the record and returns it to the pool, its storage of items is kept for the next decoding.
+func (rec *Record) release() {
+	rec.Cat = 0
+	rec.Fspec = nil
+	rec.plan = nil
+	rec.block = nil
+	rec.dropped = 0
+	rec.Items = rec.Items[:0]
+	rec.fspec = rec.fspec[:0]
+	rec.fixed = rec.fixed[:0]
+	rec.extended = rec.extended[:0]
+	rec.explicit = rec.explicit[:0]
+	rec.repetitive = rec.repetitive[:0]
+	rec.compound = rec.compound[:0]
+	rec.sp = rec.sp[:0]
+	rec.subItems = rec.subItems[:0]
+	recordPool.Put(rec)
+}
+
+// readItem decodes the compiled data field pf at the cursor into item, without reflection nor copy of the data.
+// The fields of a RFS data field are read with their compiled data field in fields.
+// The structs of the item are allocated from the storage of the record.
+func (rec *Record) readItem(c *cursor, pf *planField, fields []planField, item *Item) error {
+	*item = Item{Meta: pf.meta}
+	switch pf.field.Type {
+	case uap.Fixed:
+		data, err := c.next(pf.size)
+		if err != nil {
+			return err
+		}
+		rec.fixed = append(rec.fixed, Fixed{Data: data})
+		item.Fixed = &rec.fixed[len(rec.fixed)-1]
+
+	case uap.Extended:
+		tmp, err := readExtended(c, pf.size, pf.secondary)
+		if err != nil {
+			return err
+		}
+		rec.extended = append(rec.extended, tmp)
+		item.Extended = &rec.extended[len(rec.extended)-1]
+
+	case uap.Explicit:
+		tmp := Explicit{}
+		var err error
+		tmp.Len, tmp.Data, err = readLenData(c)
+		if err != nil {
+			return err
+		}
+		rec.explicit = append(rec.explicit, tmp)
+		item.Explicit = &rec.explicit[len(rec.explicit)-1]
+
+	case uap.Repetitive:
+		tmp := Repetitive{}
+		var err error
+		tmp.Rep, err = c.byte()
+		if err != nil {
+			return err
+		}
+		tmp.Data, err = c.next(int(tmp.Rep) * pf.size)
+		if err != nil {
+			return err
+		}
+		rec.repetitive = append(rec.repetitive, tmp)
+		item.Repetitive = &rec.repetitive[len(rec.repetitive)-1]
+
+	case uap.Compound:
+		cp, err := rec.readCompound(c, pf.sub)
+		if err != nil {
+			return err
+		}
+		item.Compound = cp
+
+	case uap.SP, uap.RE:
+		tmp := SpecialPurpose{}
+		var err error
+		tmp.Len, tmp.Data, err = readLenData(c)
+		if err != nil {
+			return err
+		}
+		if len(pf.sub) != 0 {
+			sub := cursor{data: tmp.Data}
+			tmp.Compound, err = rec.readCompound(&sub, pf.sub)
+			if err != nil || sub.len() != 0 {
+				return ErrExpansionInvalid
+			}
+		}
+		rec.sp = append(rec.sp, tmp)
+		item.SP = &rec.sp[len(rec.sp)-1]
+
+	case uap.RFS:
+		tmp, err := rec.readRFS(c, fields)
+		if err != nil {
+			return err
+		}
+		item.RFS = tmp
+
+	default:
+		return ErrDataFieldUnknown
+	}
+	return nil
+}
+
+// readExtended returns an Extended item, see ExtendedDataFieldReader.
+func readExtended(c *cursor, primarySize int, secondarySize int) (Extended, error) {
+	item := Extended{}
+	if primarySize == 0 || secondarySize == 0 {
+		return item, ErrUAPInvalid
+	}
+
+	var err error
+	item.Primary, err = c.next(primarySize)
+	if err != nil {
+		return item, err
+	}
+	if item.Primary[primarySize-1]&0x01 == 0 {
+		return item, nil
+	}
+	start, end := c.pos, c.pos
+	for {
+		part, err := c.next(secondarySize)
+		if err != nil {
+			if end > start {
+				item.Secondary = c.data[start:end:end] // the secondary parts read
+			}
+			return item, err
+		}
+		end = c.pos
+		if part[secondarySize-1]&0x01 == 0 {
+			break
+		}
+	}
+	item.Secondary = c.data[start:end:end]
+	return item, nil
+}
+
+// readLenData returns the length indicator and the data of an Explicit, SP or RE field.
+func readLenData(c *cursor) (uint8, []byte, error) {
+	length, err := c.byte()
+	if err != nil {
+		return length, nil, err
+	}
+	if length == 0 {
+		return length, nil, ErrLenInvalid
+	}
+	data, err := c.next(int(length) - 1)
+	return length, data, err
+}
+
+// readCompound returns a Compound item, see CompoundDataFieldReader.
+// The data subfields are allocated from the storage of the record. On error, it returns the subfields read.
+func (rec *Record) readCompound(c *cursor, cp []planField) (*Compound, error) {
+	primary, err := c.fspec()
+	if err != nil {
+		return nil, err
+	}
+
+	start := len(rec.subItems)
+	for frn := nextFRN(primary, 0); frn != 0 && err == nil; frn = nextFRN(primary, frn) {
+		if int(frn) > len(cp) {
+			err = ErrFRNUnknown
+			break
+		}
+		pf := &cp[frn-1]
+		switch pf.field.Type {
+		case uap.Fixed, uap.Extended, uap.Explicit, uap.Repetitive:
+			var sub Item
+			if err = rec.readItem(c, pf, nil, &sub); err == nil {
+				rec.subItems = append(rec.subItems, sub)
+			}
+
+		default:
+			err = ErrDataFieldUnknown
+		}
+	}
+
+	rec.compound = append(rec.compound, Compound{Primary: primary})
+	tmp := &rec.compound[len(rec.compound)-1]
+	if end := len(rec.subItems); end > start {
+		tmp.Secondary = rec.subItems[start:end:end]
+	}
+	return tmp, err
+}
+
+// readRFS returns a RFS item, see RFSDataFieldReader. On error, it returns the data fields read.
+func (rec *Record) readRFS(c *cursor, items []planField) (*RandomFieldSequencing, error) {
+	rfs := &RandomFieldSequencing{}
+	var err error
+	rfs.N, err = c.byte()
+	if err != nil {
+		return nil, err
+	}
+	for i := uint8(0); i < rfs.N; i++ {
+		frn, err := c.byte()
+		if err != nil {
+			return rfs, err
+		}
+
+		pf := rfsPlanField(frn, items)
+		if pf == nil {
+			return rfs, ErrFRNUnknown
+		}
+		if pf.field.Type == uap.RFS {
+			return rfs, ErrDataFieldUnknown
+		}
+		rf := RandomField{FRN: frn}
+		if err := rec.readItem(c, pf, nil, &rf.Field); err != nil {
+			return rfs, err
+		}
+		rfs.Sequence = append(rfs.Sequence, rf)
+	}
+	return rfs, nil
+}
+
+// rfsPlanField returns the compiled data field of a FRN, nil if it is absent of items.
+func rfsPlanField(frn uint8, items []planField) *planField {
+	for i := range items {
+		if items[i].field.FRN == frn {
+			return &items[i]
+		}
+	}
+	return nil
+}
+
+// skipItem moves the cursor after the compiled data field pf without decoding it, it returns the errors of readItem.
+// The fields of a RFS data field are skipped with their compiled data field in fields.
+func skipItem(c *cursor, pf *planField, fields []planField) error {
+	var err error
+	switch pf.field.Type {
+	case uap.Fixed:
+		_, err = c.next(pf.size)
+
+	case uap.Extended:
+		_, err = readExtended(c, pf.size, pf.secondary)
+
+	case uap.Explicit:
+		_, _, err = readLenData(c)
+
+	case uap.Repetitive:
+		var rep uint8
+		rep, err = c.byte()
+		if err == nil {
+			_, err = c.next(int(rep) * pf.size)
+		}
+
+	case uap.Compound:
+		err = skipCompound(c, pf.sub)
+
+	case uap.SP, uap.RE:
+		var data []byte
+		_, data, err = readLenData(c)
+		if err == nil && len(pf.sub) != 0 {
+			sub := cursor{data: data}
+			if skipCompound(&sub, pf.sub) != nil || sub.len() != 0 {
+				err = ErrExpansionInvalid
+			}
+		}
+
+	case uap.RFS:
+		err = skipRFS(c, fields)
+
+	default:
+		err = ErrDataFieldUnknown
+	}
+	return err
+}
+
+// skipCompound moves the cursor after a Compound item, see readCompound.
+func skipCompound(c *cursor, cp []planField) error {
+	primary, err := c.fspec()
+	if err != nil {
+		return err
+	}
+	for frn := nextFRN(primary, 0); frn != 0; frn = nextFRN(primary, frn) {
+		if int(frn) > len(cp) {
+			return ErrFRNUnknown
+		}
+		pf := &cp[frn-1]
+		switch pf.field.Type {
+		case uap.Fixed, uap.Extended, uap.Explicit, uap.Repetitive:
+			if err := skipItem(c, pf, nil); err != nil {
+				return err
+			}
+		default:
+			return ErrDataFieldUnknown
+		}
+	}
+	return nil
+}
+
+// skipRFS moves the cursor after a RFS item, see readRFS.
+func skipRFS(c *cursor, items []planField) error {
+	n, err := c.byte()
+	if err != nil {
+		return err
+	}
+	for i := uint8(0); i < n; i++ {
+		frn, err := c.byte()
+		if err != nil {
+			return err
+		}
+		pf := rfsPlanField(frn, items)
+		if pf == nil {
+			return ErrFRNUnknown
+		}
+		if pf.field.Type == uap.RFS {
+			return ErrDataFieldUnknown
+		}
+		if err := skipItem(c, pf, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
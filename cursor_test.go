@@ -0,0 +1,102 @@
+package goasterix
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestCursorNext(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		data         []byte
+		n            int
+		output       []byte
+		err          error
+		unRead       int
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "enough bytes",
+			data:         []byte{0x01, 0x02, 0x03},
+			n:            2,
+			output:       []byte{0x01, 0x02},
+			err:          nil,
+			unRead:       1,
+		},
+		{
+			TestCaseName: "zero byte",
+			data:         []byte{},
+			n:            0,
+			output:       []byte{},
+			err:          nil,
+			unRead:       0,
+		},
+		{
+			TestCaseName: "no byte left",
+			data:         []byte{},
+			n:            1,
+			output:       nil,
+			err:          io.EOF,
+			unRead:       0,
+		},
+		{
+			TestCaseName: "fewer bytes left",
+			data:         []byte{0x01, 0x02},
+			n:            3,
+			output:       nil,
+			err:          io.ErrUnexpectedEOF,
+			unRead:       0,
+		},
+	}
+
+	for _, row := range dataSet {
+		// Arrange
+		c := cursor{data: row.data}
+
+		// Act
+		b, err := c.next(row.n)
+
+		// Assert
+		if !errors.Is(err, row.err) || !bytes.Equal(b, row.output) || c.len() != row.unRead {
+			t.Errorf("FAIL: %s - next = % X, %v, unRead = %d; Expected: % X, %v, %d",
+				row.TestCaseName, b, err, c.len(), row.output, row.err, row.unRead)
+		} else {
+			t.Logf("SUCCESS: next = % X, %v, unRead = %d", b, err, c.len())
+		}
+	}
+}
+
+func TestNextFRN(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		fspec        []byte
+	}
+	dataSet := []dataTest{
+		{TestCaseName: "one octet", fspec: []byte{0xaa}},
+		{TestCaseName: "two octets", fspec: []byte{0xfd, 0x80}},
+		{TestCaseName: "trailing zero octet", fspec: []byte{0x01, 0x00}},
+		{TestCaseName: "beyond FRN 255", fspec: bytes.Repeat([]byte{0xff}, 40)},
+	}
+
+	for _, row := range dataSet {
+		// Arrange
+		output := FspecIndex(row.fspec)
+
+		// Act
+		var frns []uint8
+		for frn := nextFRN(row.fspec, 0); frn != 0; frn = nextFRN(row.fspec, frn) {
+			frns = append(frns, frn)
+		}
+
+		// Assert
+		if !bytes.Equal(frns, output) {
+			t.Errorf("FAIL: %s - frns = %v; Expected: %v", row.TestCaseName, frns, output)
+		} else {
+			t.Logf("SUCCESS: frns = %v; Expected: %v", frns, output)
+		}
+	}
+}
@@ -2,10 +2,8 @@
 package goasterix
 
 import (
-	"bytes"
-	"encoding/binary"
 	"errors"
-	"github.com/mokhtarimokhtar/goasterix/uap"
+	"fmt"
 )
 
 var (
@@ -29,12 +27,18 @@ func NewWrapperDataBlock() (*WrapperDataBlock, error) {
 	return w, nil
 }
 
+// Decode extracts the data blocks with the default Decoder (uap.DefaultProfiles).
+// It returns the number of bytes unRead and fills the DataBlocks array.
 func (w *WrapperDataBlock) Decode(data []byte) (unRead int, err error) {
-	offset := uint16(0)
+	return w.decode(data, defaultDecoder)
+}
+
+func (w *WrapperDataBlock) decode(data []byte, d *Decoder) (unRead int, err error) {
+	offset := 0
 	for {
 		db := NewDataBlock()
-		unRead, err := db.Decode(data[offset:])
-		offset += db.Len
+		unRead, err := db.decode(data[offset:], d)
+		offset += int(db.Len)
 		if err != nil {
 			return unRead, err
 		}
@@ -47,13 +51,120 @@ func (w *WrapperDataBlock) Decode(data []byte) (unRead int, err error) {
 	return unRead, err
 }
 
+// Release returns the records of the data blocks to a pool, see DataBlock.Release.
+func (w *WrapperDataBlock) Release() {
+	for _, db := range w.DataBlocks {
+		db.Release()
+	}
+	w.DataBlocks = nil
+}
+
+// BlockError reports the failure of one data block decoded by WrapperDataBlock.DecodeTolerant.
+// Offset is the position in byte of the data block in the wrapper data.
+type BlockError struct {
+	Offset   int
+	Category uint8
+	Err      error
+}
+
+func (e *BlockError) Error() string {
+	return fmt.Sprintf("[ASTERIX] data block CAT%03d at offset %d: %v", e.Category, e.Offset, e.Err)
+}
+
+func (e *BlockError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeTolerant extracts the data blocks like Decode but it does not stop on the first error.
+// A data block which can not be decoded is skipped using its LEN field when it is followed by a chain of plausible
+// data block headers (known CAT + LEN), otherwise the data is scanned forward from the next byte for such a chain.
+// DataBlocks contains only the data blocks successfully decoded, it returns the list of failures.
+func (w *WrapperDataBlock) DecodeTolerant(data []byte) []*BlockError {
+	return w.decodeTolerant(data, defaultDecoder)
+}
+
+func (w *WrapperDataBlock) decodeTolerant(data []byte, d *Decoder) []*BlockError {
+	var errs []*BlockError
+	offset := 0
+	for offset < len(data) {
+		remaining := data[offset:]
+		if len(remaining) < 3 {
+			errs = append(errs, &BlockError{Offset: offset, Category: remaining[0], Err: ErrUndersized})
+			break
+		}
+
+		length := int(remaining[1])<<8 + int(remaining[2])
+		if length < 3 || length > len(remaining) {
+			err := ErrLenInvalid
+			if length > len(remaining) {
+				err = ErrUndersized
+			}
+			errs = append(errs, &BlockError{Offset: offset, Category: remaining[0], Err: err})
+			offset = d.resyncDataBlock(data, offset+1)
+			continue
+		}
+
+		db := NewDataBlock()
+		_, err := db.decode(remaining[:length], d)
+		if err == nil {
+			w.DataBlocks = append(w.DataBlocks, db)
+			offset += length
+			continue
+		}
+		errs = append(errs, &BlockError{Offset: offset, Category: db.Category, Err: err})
+		if d.plausibleHeaders(data, offset+length) {
+			offset += length
+		} else {
+			offset = d.resyncDataBlock(data, offset+1) // the LEN field is wrong
+		}
+	}
+	return errs
+}
+
+// maxResyncHeaders is the number of consecutive plausible data block headers which resynchronises
+// WrapperDataBlock.DecodeTolerant, unless the end of data is reached before.
+const maxResyncHeaders = 3
+
+// resyncDataBlock returns the offset of the first plausible data block header from start, or len(data) if none.
+func (d *Decoder) resyncDataBlock(data []byte, start int) int {
+	for i := start; i+3 <= len(data); i++ {
+		if d.plausibleHeaders(data, i) {
+			return i
+		}
+	}
+	return len(data)
+}
+
+// plausibleHeaders returns true if the data blocks beginning at offset are plausible: each header has a known
+// category and a LEN which fits in the data, until the end of data or maxResyncHeaders headers.
+func (d *Decoder) plausibleHeaders(data []byte, offset int) bool {
+	for n := 0; n < maxResyncHeaders && offset != len(data); n++ {
+		if offset+3 > len(data) {
+			return false
+		}
+		if _, found := d.profiles[data[offset]]; !found {
+			return false
+		}
+		length := int(data[offset+1])<<8 + int(data[offset+2])
+		if length <= 3 || offset+length > len(data) {
+			return false
+		}
+		offset += length
+	}
+	return true
+}
+
 // DataBlock
 // a DataBlock corresponds to one (only) category and contains one or more Records.
 // DataBlock = CAT + LEN + [FSPEC + items...] + [...] + ...
+// Warnings contains the violations of the specification found in strict mode (see WithStrict).
+// Raw contains the undecoded records of a data block of unknown category (see WithRawUnknown).
 type DataBlock struct {
 	Category uint8
 	Len      uint16
 	Records  []*Record
+	Warnings []Warning
+	Raw      []byte
 }
 
 func NewDataBlock() *DataBlock {
@@ -63,68 +174,154 @@ func NewDataBlock() *DataBlock {
 // Decode extracts an asterix data block: CAT + LEN + N * RECORD(S).
 // An asterix data block can contain a or more records.
 // It returns the number of bytes unRead and fills the DataBlock Struct(Category, Len, Records array) in byte.
+// The UAP is selected with the default Decoder (uap.DefaultProfiles).
 func (db *DataBlock) Decode(data []byte) (int, error) {
+	return db.decode(data, defaultDecoder)
+}
+
+func (db *DataBlock) decode(data []byte, d *Decoder) (int, error) {
 	var unRead int
 	var err error
-	rb := bytes.NewReader(data)
+	c := cursor{data: data}
 
 	// retrieve category field
-	err = binary.Read(rb, binary.BigEndian, &db.Category)
+	db.Category, err = c.byte()
 	if err != nil {
-		unRead = rb.Len()
+		unRead = c.len()
 		return unRead, err // err = io.EOF
 	}
 
 	// retrieve length field
-	err = binary.Read(rb, binary.BigEndian, &db.Len)
+	length, err := c.next(2)
 	if err != nil {
-		unRead = rb.Len()
+		unRead = c.len()
+		return unRead, err
+	}
+	db.Len = uint16(length[0])<<8 + uint16(length[1])
+	if db.Len < 3 {
+		db.Records = nil
+		err = ErrLenInvalid
+		unRead = c.len()
 		return unRead, err
 	}
 	// check if the rest is big enough
-	rbSize := uint16(rb.Size())
-	if rbSize < db.Len {
+	if len(data) < int(db.Len) {
 		db.Records = nil
 		err = ErrUndersized
-		unRead = rb.Len()
+		unRead = c.len()
 		return unRead, err
 	}
 
-	// retrieve records
-	tmp := make([]byte, db.Len-3)
-	_ = binary.Read(rb, binary.BigEndian, tmp) // ignore explicit error because it's detect before
-	unRead = rb.Len()
+	// retrieve records, the items are slices of tmp
+	tmp := data[3:db.Len:db.Len]
+	if !d.alias {
+		tmp = make([]byte, db.Len-3)
+		copy(tmp, data[3:db.Len])
+	}
+	unRead = len(data) - int(db.Len)
 
 	// decode N * records
 	offset := 0
 	lenData := len(tmp)
 
 	// selection of the appropriate UAP
-	uapSelected, found := uap.DefaultProfiles[db.Category]
-	if !found {
+	if _, found := d.profiles[db.Category]; !found && d.resolver == nil {
+		if d.raw {
+			db.Raw = tmp
+			return unRead, nil
+		}
 		err = ErrCategoryUnknown
 		return unRead, err
 	}
 
+	var starts []int // offsets of the records from the CAT field
+	var p *Plan
+	dropped := 0 // bytes of the items not selected, see WithSelection
 LoopRecords:
 	for {
-		rec := NewRecord()
-		unRead, err := rec.Decode(tmp[offset:], uapSelected)
+		var found bool
+		p, found = d.recordPlan(db.Category, tmp[offset:], p)
+		if !found && d.raw {
+			db.Release()
+			db.Raw = tmp
+			return unRead, nil
+		}
+		if !found {
+			err = ErrCategoryUnknown
+			return unRead, err
+		}
+
+		starts = append(starts, 3+offset)
+		rec := getRecord()
+		rec.block = db
+		unRead, warnings, err := rec.decode(tmp[offset:], p, d.strict)
 		db.Records = append(db.Records, rec)
-		offset = lenData - unRead
+		for _, w := range warnings {
+			w.Offset += starts[len(starts)-1] // from the CAT field
+			w.Record = len(db.Records) - 1
+			db.Warnings = append(db.Warnings, w)
+		}
 
 		if err != nil {
+			var decodeErr *DecodeError
+			if errors.As(err, &decodeErr) {
+				decodeErr.Offset += 3 + offset // from the CAT field
+				decodeErr.Record = len(db.Records) - 1
+			}
 			return unRead, err
 		}
+		dropped += rec.dropped
+		offset = lenData - unRead
 		// offset == lenData is for the case payload is oversize of LEN field asterix
 		// if unRead == 0 || offset == lenData {
 		if unRead == 0 {
 			break LoopRecords
 		}
 	}
+	if d.strict {
+		db.Warnings = append(db.Warnings, db.paddingWarnings(data, starts)...)
+	}
+	db.Len -= uint16(dropped) // the LEN of the items kept
 	return unRead, nil
 }
 
+// paddingWarnings returns the warnings of the zero bytes decoded as empty records at the end of the data block
+// and of the zero bytes following the data block at the end of data, starts are the offsets of the records.
+func (db *DataBlock) paddingWarnings(data []byte, starts []int) []Warning {
+	var warnings []Warning
+	first := len(db.Records)
+	for first > 0 && isZero(db.Records[first-1].Fspec) {
+		first--
+	}
+	if first < len(db.Records) {
+		warnings = append(warnings, Warning{Kind: WarnLenInconsistent, Offset: starts[first], Category: db.Category, Record: first})
+	}
+	if int(db.Len) < len(data) && isZero(data[db.Len:]) {
+		warnings = append(warnings, Warning{Kind: WarnPadding, Offset: int(db.Len), Category: db.Category, Record: len(db.Records)})
+	}
+	return warnings
+}
+
+func isZero(data []byte) bool {
+	for _, b := range data {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Release returns the records of the data block to a pool: their items are reused by the next decodings
+// instead of being allocated. The DataBlock, its records and their items must not be used afterwards.
+func (db *DataBlock) Release() {
+	for _, rec := range db.Records {
+		rec.release()
+	}
+	db.Records = nil
+	db.Warnings = nil
+	db.Raw = nil
+}
+
 func (db DataBlock) String() [][]string {
 	var records [][]string
 	for _, record := range db.Records {
@@ -148,6 +345,9 @@ func (db DataBlock) Payload() [][]byte {
 	for _, record := range db.Records {
 		pd = append(pd, record.Payload())
 	}
+	if db.Raw != nil {
+		pd = append(pd, db.Raw)
+	}
 	return pd
 }
 
@@ -1,6 +1,8 @@
 package goasterix
 
 import (
+	"bytes"
+	"errors"
 	"github.com/mokhtarimokhtar/goasterix/util"
 	"io"
 	"testing"
@@ -70,7 +72,7 @@ func TestWrapperDataBlockDecode(t *testing.T) {
 		unRead, err := w.Decode(data)
 
 		// Assert
-		if err != row.err {
+		if !errors.Is(err, row.err) {
 			t.Errorf("FAIL: error: %s; Expected: %v", err, row.err)
 		} else {
 			t.Logf("SUCCESS: error: %v; Expected: %v", err, row.err)
@@ -88,6 +90,94 @@ func TestWrapperDataBlockDecode(t *testing.T) {
 	}
 }
 
+func TestWrapperDataBlockDecodeTolerant(t *testing.T) {
+	// setup
+	cat048 := packInput[:560] // CAT048 data block of 280 bytes
+	type dataTest struct {
+		TestCaseName   string
+		input          string
+		nbOfDataBlocks int
+		errs           []BlockError
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName:   "without error",
+			input:          "220014f6083602429b7110940028200094008000 220014f6083602429b7110940028200094008000",
+			nbOfDataBlocks: 2,
+			errs:           nil,
+		},
+		{
+			TestCaseName:   "unknown category skipped by LEN",
+			input:          "220014f6083602429b7110940028200094008000 000005ffff 220014f6083602429b7110940028200094008000",
+			nbOfDataBlocks: 2,
+			errs:           []BlockError{{Offset: 20, Category: 0, Err: ErrCategoryUnknown}},
+		},
+		{
+			TestCaseName:   "corrupt record skipped by LEN",
+			input:          "220014f6083602429b7110940028200094008000 220008f608360242 220014f6083602429b7110940028200094008000",
+			nbOfDataBlocks: 2,
+			errs:           []BlockError{{Offset: 20, Category: 34, Err: io.ErrUnexpectedEOF}},
+		},
+		{
+			TestCaseName:   "garbage LEN resynchronised on next header",
+			input:          "220014f6083602429b7110940028200094008000 abcdef1234 220014f6083602429b7110940028200094008000",
+			nbOfDataBlocks: 2,
+			errs:           []BlockError{{Offset: 20, Category: 0xab, Err: ErrUndersized}},
+		},
+		{
+			TestCaseName:   "LEN smaller than header",
+			input:          "220014f6083602429b7110940028200094008000 220001 220014f6083602429b7110940028200094008000",
+			nbOfDataBlocks: 2,
+			errs:           []BlockError{{Offset: 20, Category: 34, Err: ErrLenInvalid}},
+		},
+		{
+			TestCaseName:   "trailing bytes",
+			input:          "220014f6083602429b7110940028200094008000 2200",
+			nbOfDataBlocks: 1,
+			errs:           []BlockError{{Offset: 20, Category: 34, Err: ErrUndersized}},
+		},
+		{
+			TestCaseName:   "wrong LEN in range resynchronised on next header",
+			input:          cat048 + "300200" + cat048[6:] + cat048 + cat048,
+			nbOfDataBlocks: 3,
+			errs:           []BlockError{{Offset: 280, Category: 48, Err: ErrFRNUnknown}},
+		},
+		{
+			TestCaseName:   "false header in garbage",
+			input:          "30ffff010203" + cat048,
+			nbOfDataBlocks: 1,
+			errs:           []BlockError{{Offset: 0, Category: 48, Err: ErrUndersized}},
+		},
+	}
+
+	for _, row := range dataSet {
+		// Arrange
+		data, _ := util.HexStringToByte(row.input)
+		w, _ := NewWrapperDataBlock()
+
+		// Act
+		errs := w.DecodeTolerant(data)
+
+		// Assert
+		if len(w.DataBlocks) != row.nbOfDataBlocks {
+			t.Errorf("FAIL: %s - nbOfDataBlocks = %v; Expected: %v", row.TestCaseName, len(w.DataBlocks), row.nbOfDataBlocks)
+		} else {
+			t.Logf("SUCCESS: nbOfDataBlocks = %v; Expected: %v", len(w.DataBlocks), row.nbOfDataBlocks)
+		}
+		if len(errs) != len(row.errs) {
+			t.Errorf("FAIL: %s - nbOfErrors = %v; Expected: %v", row.TestCaseName, len(errs), len(row.errs))
+			continue
+		}
+		for i, err := range errs {
+			if err.Offset != row.errs[i].Offset || err.Category != row.errs[i].Category || !errors.Is(err, row.errs[i].Err) {
+				t.Errorf("FAIL: %s - error: %v; Expected: %v", row.TestCaseName, err, &row.errs[i])
+			} else {
+				t.Logf("SUCCESS: error: %v; Expected: %v", err, &row.errs[i])
+			}
+		}
+	}
+}
+
 // DataBlock Testing
 // a DataBlock correspond to one (only) category and contains one or more Records.
 // DataBlock = CAT + LEN + [FSPEC + items...] + [...] + ...
@@ -223,6 +313,13 @@ func TestDataBlockDecode(t *testing.T) {
 			nbOfRecords:  47,
 			unRead:       0,
 		},
+		{
+			TestCaseName: "CAT034: LEN smaller than header",
+			input:        "220002f6",
+			err:          ErrLenInvalid,
+			nbOfRecords:  0,
+			unRead:       1,
+		},
 		{
 			TestCaseName: "CAT034: over sized data block",
 			input:        "220014f6081002412998d89400002000940000811a",
@@ -241,7 +338,7 @@ func TestDataBlockDecode(t *testing.T) {
 		unRead, err := dataB.Decode(data)
 
 		// Assert
-		if err != row.err {
+		if !errors.Is(err, row.err) {
 			t.Errorf("FAIL: %s error: %s; Expected: %v", row.TestCaseName, err, row.err)
 		} else {
 			t.Logf("SUCCESS: error: %v; Expected: %v", err, row.err)
@@ -317,7 +414,7 @@ func TestDataBlockDecode_ARTAS(t *testing.T) {
 		unRead, err := dataB.Decode(data)
 
 		// Assert
-		if err != row.err {
+		if !errors.Is(err, row.err) {
 			t.Errorf("FAIL: error: %s; Expected: %v", err, row.err)
 		} else {
 			t.Logf("SUCCESS: error: %v; Expected: %v", err, row.err)
@@ -335,6 +432,85 @@ func TestDataBlockDecode_ARTAS(t *testing.T) {
 	}
 }
 
+func TestDataBlockDecode_DecodeError(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		input        string
+		output       DecodeError
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "first record",
+			input:        "1a 0026 FD80 FFFF FFFE AAFFFFFE 02FFFF FFFF 03FFFF 02FFFFFFFF 04FFFFFF 0101FFFF 05FFFFFF",
+			output:       DecodeError{Offset: 10, Category: 26, Record: 0, FRN: 3, DataItem: "I026/003", Err: io.ErrUnexpectedEOF},
+		},
+		{
+			TestCaseName: "second record",
+			input:        "22 0017 f6083602429b7110940028200094008000 f60836",
+			output:       DecodeError{Offset: 23, Category: 34, Record: 1, FRN: 2, DataItem: "I034/000", Err: io.EOF},
+		},
+		{
+			TestCaseName: "FSPEC",
+			input:        "22 0016 f6083602429b7110940028200094008000 ff ff",
+			output:       DecodeError{Offset: 20, Category: 34, Record: 1, FRN: 0, DataItem: "", Err: io.EOF},
+		},
+	}
+
+	for _, row := range dataSet {
+		// Arrange
+		data, _ := util.HexStringToByte(row.input)
+		dataB := NewDataBlock()
+
+		// Act
+		_, err := dataB.Decode(data)
+
+		// Assert
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) {
+			t.Errorf("FAIL: %s - error: %v; Expected: %v", row.TestCaseName, err, &row.output)
+			continue
+		}
+		if *decodeErr != row.output {
+			t.Errorf("FAIL: %s - error: %v; Expected: %v", row.TestCaseName, decodeErr, &row.output)
+		} else {
+			t.Logf("SUCCESS: error: %v; Expected: %v", decodeErr, &row.output)
+		}
+		if !errors.Is(err, row.output.Err) {
+			t.Errorf("FAIL: %s - error: %v; Expected: %v", row.TestCaseName, err, row.output.Err)
+		}
+	}
+}
 
+func TestDataBlockRelease(t *testing.T) {
+	// Arrange
+	input := "300118fff7020836429b52a094c70181091302d06002b7490d0138a178cf422002e79a5d27a00c0060a3280030a4000040063a0743ce5b4020f5fff7020836429b54e000bc020901a2005c7802e800263946e50464b1cb6ca0029ea9491062a4546093880032d4000040059602f639590220f5fff7020836429b58a0909703ff026405a26002bb4066740815f6e795e002e56a0530ffdff860b0d80032fc00004003cf0810c9ef4020fdfff7020836429b56a0775d03700ec205786002be4060910815f9c363a002a49a0f30bfffff60c4600030a4000040057207674a004020fdfff7020836429b55a0468c029804b105786002c57101124d6070d3282002adfa3333a0140060c4600030a4000040026e07d75fc04020f5"
+	data, _ := util.HexStringToByte(input)
+	other, _ := util.HexStringToByte("220014f6083602429b7110940028200094008000")
+	d := NewDecoder(WithAliasing())
 
+	// Act
+	for i := 0; i < 3; i++ {
+		tmp, _, _ := d.DecodeDataBlock(other)
+		tmp.Release()
+	}
+	db, _, err := d.DecodeDataBlock(data)
 
+	// Assert
+	if err != nil {
+		t.Fatalf("FAIL: error = %v; Expected: %v", err, nil)
+	}
+	var payload []byte
+	for _, pd := range db.Payload() {
+		payload = append(payload, pd...)
+	}
+	if !bytes.Equal(payload, data) {
+		t.Errorf("FAIL: payload = %X; Expected: %X", payload, data)
+	} else {
+		t.Logf("SUCCESS: payload = %X; Expected: %X", payload, data)
+	}
+	db.Release()
+	if db.Records != nil {
+		t.Errorf("FAIL: records = %v; Expected: %v", db.Records, nil)
+	}
+}
@@ -0,0 +1,229 @@
+package goasterix
+
+import (
+	"github.com/mokhtarimokhtar/goasterix/uap"
+)
+
+// defaultDecoder is used by the package-level decoding methods (DataBlock.Decode, WrapperDataBlock.Decode, ...).
+// For compatibility, it shares uap.DefaultProfiles instead of a copy: a change of uap.DefaultProfiles is visible,
+// the cached plans are checked against the profiles (see planCache).
+var defaultDecoder = &Decoder{profiles: uap.DefaultProfiles, plans: &planCache{}}
+
+// Decoder decodes asterix data blocks with its own registry of User Application Profiles.
+// The registry is copied when the Decoder is created and never modified afterwards: a Decoder is immutable,
+// it can be used by several goroutines and several decoders can use different profiles for the same category.
+type Decoder struct {
+	profiles map[uint8]uap.StandardUAP
+	plans    *planCache
+	resolver ProfileResolver
+	strict   bool
+	alias    bool
+	raw      bool
+}
+
+// DataSource identifies the source of a record: SAC (System Area Code) and SIC (System Identification Code)
+// of the Data Source Identifier item (FRN 1).
+type DataSource struct {
+	Sac uint8
+	Sic uint8
+}
+
+// ProfileResolver selects the User Application Profile of each record from its category and its data source.
+// hasSource is false when the record does not contain a Data Source Identifier.
+// It returns false to use the profile registered in the Decoder for the category.
+type ProfileResolver func(category uint8, src DataSource, hasSource bool) (uap.StandardUAP, bool)
+
+// SourceKey identifies a data source of a category.
+type SourceKey struct {
+	Category uint8
+	Source   DataSource
+}
+
+// BySource returns a ProfileResolver selecting the profile of a record from its category and its data source,
+// the records of a data source absent of profiles are decoded with the registered profile.
+// e.g. BySource(map[SourceKey]uap.StandardUAP{{30, DataSource{Sac: 0x08, Sic: 0x84}}: uap.Cat030ArtasV62})
+func BySource(profiles map[SourceKey]uap.StandardUAP) ProfileResolver {
+	tmp := make(map[SourceKey]uap.StandardUAP, len(profiles))
+	for key, stdUAP := range profiles {
+		tmp[key] = stdUAP
+	}
+	return func(category uint8, src DataSource, hasSource bool) (uap.StandardUAP, bool) {
+		if !hasSource {
+			return uap.StandardUAP{}, false
+		}
+		stdUAP, found := tmp[SourceKey{Category: category, Source: src}]
+		return stdUAP, found
+	}
+}
+
+// DecoderOption configures a Decoder when it is created by NewDecoder.
+type DecoderOption func(d *Decoder)
+
+// WithProfile registers a User Application Profile for its category, it replaces the previous one.
+func WithProfile(stdUAP uap.StandardUAP) DecoderOption {
+	return func(d *Decoder) {
+		d.profiles[stdUAP.Category] = stdUAP
+	}
+}
+
+// WithProfiles replaces the whole registry of User Application Profiles, the categories not contained
+// in profiles are unknown for the Decoder.
+func WithProfiles(profiles map[uint8]uap.StandardUAP) DecoderOption {
+	return func(d *Decoder) {
+		d.profiles = make(map[uint8]uap.StandardUAP, len(profiles))
+		for cat, stdUAP := range profiles {
+			d.profiles[cat] = stdUAP
+		}
+	}
+}
+
+// WithResolver selects the profile of each record with a ProfileResolver, e.g. when the sensors of one capture
+// send the same category with different UAP editions. The selection per input stream is done by using
+// one Decoder for each stream.
+func WithResolver(resolver ProfileResolver) DecoderOption {
+	return func(d *Decoder) {
+		d.resolver = resolver
+	}
+}
+
+// WithAliasing decodes the data blocks without copy: the items of the records are slices of the data decoded.
+// The data must not be modified while the DataBlock is used, e.g. a DataBlock returned by Reader.Next is valid
+// until the next call of Next.
+// The allocations are saved with WithAliasing and DataBlock.Release together: otherwise the data of each data block
+// is copied and its records are allocated (see the _Aliasing benchmarks).
+func WithAliasing() DecoderOption {
+	return func(d *Decoder) {
+		d.alias = true
+	}
+}
+
+// WithRawUnknown keeps the data blocks of unknown categories (without profile) undecoded instead of failing with
+// ErrCategoryUnknown: their records are contained in DataBlock.Raw and the decoding goes on with the next data block,
+// e.g. to forward the categories which are not decoded. The raw data blocks are encoded again unchanged.
+func WithRawUnknown() DecoderOption {
+	return func(d *Decoder) {
+		d.raw = true
+	}
+}
+
+// NewDecoder returns a Decoder with a copy of uap.DefaultProfiles modified by the options.
+// e.g. NewDecoder(WithProfile(uap.Cat030ArtasV62)) decodes CAT030 with ARTAS profile.
+// The profiles are copied with their data fields: a profile modified in place afterwards is not visible.
+func NewDecoder(options ...DecoderOption) *Decoder {
+	d := &Decoder{
+		profiles: make(map[uint8]uap.StandardUAP, len(uap.DefaultProfiles)),
+		plans:    &planCache{},
+	}
+	for cat, stdUAP := range uap.DefaultProfiles {
+		d.profiles[cat] = stdUAP
+	}
+	for _, option := range options {
+		option(d)
+	}
+	for cat, stdUAP := range d.profiles {
+		d.profiles[cat] = copyProfile(stdUAP)
+	}
+	d.plans.owned = true
+	return d
+}
+
+// Profile returns a copy of the User Application Profile registered for a category.
+func (d *Decoder) Profile(category uint8) (uap.StandardUAP, bool) {
+	stdUAP, found := d.profiles[category]
+	return copyProfile(stdUAP), found
+}
+
+// Plan returns the decoding Plan of the User Application Profile registered for a category.
+// The plans of the profiles are compiled on their first use and cached by the Decoder.
+func (d *Decoder) Plan(category uint8) (*Plan, bool) {
+	stdUAP, found := d.profiles[category]
+	if !found {
+		return nil, false
+	}
+	return d.plans.registered(stdUAP, nil), true
+}
+
+// recordPlan returns the Plan of the User Application Profile of the record beginning data,
+// last is the Plan of the previous record of the data block (nil for the first record), see planCache.
+// The plans of the profiles selected by the ProfileResolver are checked against the profiles, see planCache.
+func (d *Decoder) recordPlan(category uint8, data []byte, last *Plan) (*Plan, bool) {
+	stdUAP, found := d.profiles[category]
+	if d.resolver != nil {
+		src, hasSource := recordSource(data, stdUAP, found)
+		if tmp, ok := d.resolver(category, src, hasSource); ok {
+			return d.plans.resolved(tmp, last), true
+		}
+	}
+	if !found {
+		return nil, false
+	}
+	return d.plans.registered(stdUAP, last), true
+}
+
+// recordSource reads the Data Source Identifier of a record without decoding it.
+// The Data Source Identifier is the first item (FRN 1) of two octets, following the FSPEC.
+// When no profile is known, the category is assumed to follow this convention.
+func recordSource(data []byte, stdUAP uap.StandardUAP, found bool) (DataSource, bool) {
+	src := DataSource{}
+	if found && (len(stdUAP.Items) == 0 || stdUAP.Items[0].Type != uap.Fixed || stdUAP.Items[0].Fixed.Size != 2) {
+		return src, false
+	}
+
+	fspecLen := 0
+	for fspecLen < len(data) {
+		fspecLen++
+		if data[fspecLen-1]&0x01 == 0 {
+			break
+		}
+	}
+	if len(data) == 0 || data[0]&0x80 == 0 || fspecLen+2 > len(data) {
+		return src, false
+	}
+	src.Sac = data[fspecLen]
+	src.Sic = data[fspecLen+1]
+	return src, true
+}
+
+// DecodeDataBlock extracts an asterix data block: CAT + LEN + N * RECORD(S).
+// It returns the DataBlock and the number of bytes unRead, see DataBlock.Decode.
+func (d *Decoder) DecodeDataBlock(data []byte) (*DataBlock, int, error) {
+	db := NewDataBlock()
+	unRead, err := db.decode(data, d)
+	return db, unRead, err
+}
+
+// DecodeRecord extracts one record of a category with the profile registered in the Decoder (or selected by
+// its ProfileResolver). It returns the Record and the number of bytes unRead, see Record.Decode.
+// In strict mode (see WithStrict), it returns the violations of the specification found in the record,
+// their Offset is from the start of the record.
+func (d *Decoder) DecodeRecord(category uint8, data []byte) (*Record, int, []Warning, error) {
+	p, found := d.recordPlan(category, data, nil)
+	if !found {
+		return nil, len(data), nil, ErrCategoryUnknown
+	}
+	tmp := data
+	if !d.alias {
+		tmp = make([]byte, len(data))
+		copy(tmp, data)
+	}
+	rec := NewRecord()
+	unRead, warnings, err := rec.decode(tmp, p, d.strict)
+	return rec, unRead, warnings, err
+}
+
+// DecodeWrapper extracts one or more data blocks of one or more categories.
+// It returns the WrapperDataBlock and the number of bytes unRead, see WrapperDataBlock.Decode.
+func (d *Decoder) DecodeWrapper(data []byte) (*WrapperDataBlock, int, error) {
+	w, _ := NewWrapperDataBlock()
+	unRead, err := w.decode(data, d)
+	return w, unRead, err
+}
+
+// DecodeWrapperTolerant extracts the data blocks without stopping on the first error.
+// It returns the WrapperDataBlock of the data blocks decoded and the list of failures,
+// see WrapperDataBlock.DecodeTolerant.
+func (d *Decoder) DecodeWrapperTolerant(data []byte) (*WrapperDataBlock, []*BlockError) {
+	w, _ := NewWrapperDataBlock()
+	errs := w.decodeTolerant(data, d)
+	return w, errs
+}
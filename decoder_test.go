@@ -0,0 +1,378 @@
+package goasterix
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/mokhtarimokhtar/goasterix/uap"
+	"github.com/mokhtarimokhtar/goasterix/util"
+)
+
+func TestNewDecoder_Profiles(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		decoder      *Decoder
+		category     uint8
+		name         string
+		found        bool
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "default profiles",
+			decoder:      NewDecoder(),
+			category:     48,
+			name:         uap.Cat048V127.Name,
+			found:        true,
+		},
+		{
+			TestCaseName: "replaced profile",
+			decoder:      NewDecoder(WithProfile(uap.Cat030ArtasV70)),
+			category:     30,
+			name:         uap.Cat030ArtasV70.Name,
+			found:        true,
+		},
+		{
+			TestCaseName: "own registry",
+			decoder:      NewDecoder(WithProfiles(map[uint8]uap.StandardUAP{34: uap.Cat034V127})),
+			category:     48,
+			name:         "",
+			found:        false,
+		},
+	}
+
+	for _, row := range dataSet {
+		// Act
+		stdUAP, found := row.decoder.Profile(row.category)
+
+		// Assert
+		if found != row.found || stdUAP.Name != row.name {
+			t.Errorf("FAIL: %s - profile = %v, found = %v; Expected: %v, %v", row.TestCaseName, stdUAP.Name, found, row.name, row.found)
+		} else {
+			t.Logf("SUCCESS: profile = %v, found = %v; Expected: %v, %v", stdUAP.Name, found, row.name, row.found)
+		}
+	}
+}
+
+func TestNewDecoder_Immutable(t *testing.T) {
+	// Arrange
+	profiles := map[uint8]uap.StandardUAP{34: uap.Cat034V127}
+	d := NewDecoder(WithProfiles(profiles))
+
+	// Act
+	profiles[48] = uap.Cat048V127
+	_, found := d.Profile(48)
+
+	// Assert
+	if found {
+		t.Errorf("FAIL: found = %v; Expected: %v", found, false)
+	} else {
+		t.Logf("SUCCESS: found = %v; Expected: %v", found, false)
+	}
+}
+
+func TestDecoderDecodeDataBlock(t *testing.T) {
+	// Arrange
+	input := "220014f6083602429b7110940028200094008000"
+	data, _ := util.HexStringToByte(input)
+	d := NewDecoder()
+
+	// Act
+	db, unRead, err := d.DecodeDataBlock(data)
+
+	// Assert
+	if err != nil {
+		t.Errorf("FAIL: error: %v; Expected: %v", err, nil)
+	} else {
+		t.Logf("SUCCESS: error: %v; Expected: %v", err, nil)
+	}
+	if unRead != 0 {
+		t.Errorf("FAIL: unRead = %v; Expected: %v", unRead, 0)
+	} else {
+		t.Logf("SUCCESS: unRead = %v; Expected: %v", unRead, 0)
+	}
+	if len(db.Records) != 1 {
+		t.Errorf("FAIL: nbOfRecords = %v; Expected: %v", len(db.Records), 1)
+	} else {
+		t.Logf("SUCCESS: nbOfRecords = %v; Expected: %v", len(db.Records), 1)
+	}
+}
+
+func TestDecoderDecodeWrapper_UnknownCategory(t *testing.T) {
+	// Arrange
+	input := "220014f6083602429b7110940028200094008000"
+	data, _ := util.HexStringToByte(input)
+	d := NewDecoder(WithProfiles(map[uint8]uap.StandardUAP{48: uap.Cat048V127}))
+
+	// Act
+	_, _, err := d.DecodeWrapper(data)
+
+	// Assert
+	if err != ErrCategoryUnknown {
+		t.Errorf("FAIL: error: %v; Expected: %v", err, ErrCategoryUnknown)
+	} else {
+		t.Logf("SUCCESS: error: %v; Expected: %v", err, ErrCategoryUnknown)
+	}
+}
+
+// TestDecoder_Concurrent decodes a CAT030 ARTAS feed and a CAT030 STR feed at the same time.
+func TestDecoder_Concurrent(t *testing.T) {
+	// Arrange
+	inputArtas := "1e00f3afbbf317f1300883040070a8bcf3ff07070723f0a8800713feb7022b0389038b140704012c080811580000001e7004f04aa004b0012400544e49413531313206c84c45424c48454c584d413332300101a5389075c71ca0afbbf317f130088304002aa8bcf3ff04040447fda703f7d2008f0df705280528140700000008171158000000087002f0c3c00528012d006955414c3931202007314c4c42474b4557524842373757a290f3541339c60820afbbf31101300883040335a8bcf3ff0b0b0b2be9a9b5fffefffa0fff08c008c01d0e070000001484115800000200700400ffffffffffffffff344045df7df76021d3"
+	inputStr := "1e009fbffb0160088358052c7dfc04010e0fe86601c4720e008c008c01beff8bf027190439cc821885050e08203fff01605800847dfc04010e0a6968a7d6160e029d02a2fc660498f8feb917010c4caa2358f171dc15603ffb01605801d27dfc04010e0b1a6d60cf860e02d002d0fd460370f017010c4d02a6286076d518203ffb805805387dfc040f0e0e007593ccb20e00500050feb9ff5df017010c2205"
+	dataArtas, _ := util.HexStringToByte(inputArtas)
+	dataStr, _ := util.HexStringToByte(inputStr)
+	artas := NewDecoder(WithProfile(uap.Cat030ArtasV62))
+	str := NewDecoder(WithProfile(uap.Cat030StrV51))
+	var wg sync.WaitGroup
+	nbOfRecords := make([]int, 2)
+	errs := make([]error, 2)
+
+	// Act
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		var db *DataBlock
+		db, _, errs[0] = artas.DecodeDataBlock(dataArtas)
+		nbOfRecords[0] = len(db.Records)
+	}()
+	go func() {
+		defer wg.Done()
+		var db *DataBlock
+		db, _, errs[1] = str.DecodeDataBlock(dataStr)
+		nbOfRecords[1] = len(db.Records)
+	}()
+	wg.Wait()
+
+	// Assert
+	if errs[0] != nil || nbOfRecords[0] != 3 {
+		t.Errorf("FAIL: ARTAS error: %v, nbOfRecords = %v; Expected: %v, %v", errs[0], nbOfRecords[0], nil, 3)
+	} else {
+		t.Logf("SUCCESS: ARTAS error: %v, nbOfRecords = %v; Expected: %v, %v", errs[0], nbOfRecords[0], nil, 3)
+	}
+	if errs[1] != nil || nbOfRecords[1] != 4 {
+		t.Errorf("FAIL: STR error: %v, nbOfRecords = %v; Expected: %v, %v", errs[1], nbOfRecords[1], nil, 4)
+	} else {
+		t.Logf("SUCCESS: STR error: %v, nbOfRecords = %v; Expected: %v, %v", errs[1], nbOfRecords[1], nil, 4)
+	}
+}
+
+func TestDecoderNewReader(t *testing.T) {
+	// Arrange
+	input := "220014f6083602429b7110940028200094008000"
+	data, _ := util.HexStringToByte(input)
+	d := NewDecoder(WithProfiles(map[uint8]uap.StandardUAP{48: uap.Cat048V127}))
+	r := d.NewReader(bytes.NewReader(data))
+
+	// Act
+	_, err := r.Next()
+
+	// Assert
+	if err != ErrCategoryUnknown {
+		t.Errorf("FAIL: error: %v; Expected: %v", err, ErrCategoryUnknown)
+	} else {
+		t.Logf("SUCCESS: error: %v; Expected: %v", err, ErrCategoryUnknown)
+	}
+}
+
+func TestDecoderWithResolver_BySource(t *testing.T) {
+	// Arrange
+	// one capture with CAT030 STR (SAC/SIC 0x08/0x83) and CAT030 ARTAS (SAC/SIC 0x08/0x84)
+	input := "1e009fbffb0160088358052c7dfc04010e0fe86601c4720e008c008c01beff8bf027190439cc821885050e08203fff01605800847dfc04010e0a6968a7d6160e029d02a2fc660498f8feb917010c4caa2358f171dc15603ffb01605801d27dfc04010e0b1a6d60cf860e02d002d0fd460370f017010c4d02a6286076d518203ffb805805387dfc040f0e0e007593ccb20e00500050feb9ff5df017010c2205" +
+		"1e00f3afbbf317f1300884040070a8bcf3ff07070723f0a8800713feb7022b0389038b140704012c080811580000001e7004f04aa004b0012400544e49413531313206c84c45424c48454c584d413332300101a5389075c71ca0afbbf317f130088404002aa8bcf3ff04040447fda703f7d2008f0df705280528140700000008171158000000087002f0c3c00528012d006955414c3931202007314c4c42474b4557524842373757a290f3541339c60820afbbf31101300884040335a8bcf3ff0b0b0b2be9a9b5fffefffa0fff08c008c01d0e070000001484115800000200700400ffffffffffffffff344045df7df76021d3"
+	data, _ := util.HexStringToByte(input)
+	d := NewDecoder(
+		WithProfile(uap.Cat030StrV51),
+		WithResolver(BySource(map[SourceKey]uap.StandardUAP{
+			{Category: 30, Source: DataSource{Sac: 0x08, Sic: 0x84}}: uap.Cat030ArtasV62,
+		})),
+	)
+	nbOfRecords := []int{4, 3}
+
+	// Act
+	w, unRead, err := d.DecodeWrapper(data)
+
+	// Assert
+	if err != nil {
+		t.Errorf("FAIL: error: %v; Expected: %v", err, nil)
+	} else {
+		t.Logf("SUCCESS: error: %v; Expected: %v", err, nil)
+	}
+	if unRead != 0 {
+		t.Errorf("FAIL: unRead = %v; Expected: %v", unRead, 0)
+	} else {
+		t.Logf("SUCCESS: unRead = %v; Expected: %v", unRead, 0)
+	}
+	if len(w.DataBlocks) != len(nbOfRecords) {
+		t.Fatalf("FAIL: nbOfDataBlocks = %v; Expected: %v", len(w.DataBlocks), len(nbOfRecords))
+	}
+	for i, db := range w.DataBlocks {
+		if len(db.Records) != nbOfRecords[i] {
+			t.Errorf("FAIL: nbOfRecords = %v; Expected: %v", len(db.Records), nbOfRecords[i])
+		} else {
+			t.Logf("SUCCESS: nbOfRecords = %v; Expected: %v", len(db.Records), nbOfRecords[i])
+		}
+	}
+}
+
+func TestDecoderWithResolver_Source(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		input        string
+		src          DataSource
+		hasSource    bool
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "data source identifier",
+			input:        "220014f6083602429b7110940028200094008000",
+			src:          DataSource{Sac: 0x08, Sic: 0x36},
+			hasSource:    true,
+		},
+		{
+			TestCaseName: "without data source identifier",
+			input:        "220008 60 02 429b71",
+			src:          DataSource{},
+			hasSource:    false,
+		},
+	}
+
+	for _, row := range dataSet {
+		// Arrange
+		data, _ := util.HexStringToByte(row.input)
+		var src DataSource
+		var hasSource bool
+		d := NewDecoder(WithResolver(func(category uint8, s DataSource, ok bool) (uap.StandardUAP, bool) {
+			src, hasSource = s, ok
+			return uap.StandardUAP{}, false
+		}))
+
+		// Act
+		_, _, err := d.DecodeDataBlock(data)
+
+		// Assert
+		if err != nil {
+			t.Errorf("FAIL: %s - error: %v; Expected: %v", row.TestCaseName, err, nil)
+		}
+		if src != row.src || hasSource != row.hasSource {
+			t.Errorf("FAIL: %s - src = %v, %v; Expected: %v, %v", row.TestCaseName, src, hasSource, row.src, row.hasSource)
+		} else {
+			t.Logf("SUCCESS: src = %v, %v; Expected: %v, %v", src, hasSource, row.src, row.hasSource)
+		}
+	}
+}
+
+func TestNewDecoder_Aliasing(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		decoder      *Decoder
+		aliased      bool
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "copy by default",
+			decoder:      NewDecoder(),
+			aliased:      false,
+		},
+		{
+			TestCaseName: "aliasing",
+			decoder:      NewDecoder(WithAliasing()),
+			aliased:      true,
+		},
+	}
+
+	for _, row := range dataSet {
+		// Arrange
+		data, _ := util.HexStringToByte("220014f6083602429b7110940028200094008000")
+		db, _, err := row.decoder.DecodeDataBlock(data)
+		if err != nil {
+			t.Fatalf("FAIL: %s - error = %v; Expected: %v", row.TestCaseName, err, nil)
+		}
+
+		// Act
+		data[4] = 0xaa // SAC of I034/010
+
+		// Assert
+		aliased := db.Records[0].Items[0].Fixed.Data[0] == 0xaa
+		if aliased != row.aliased {
+			t.Errorf("FAIL: %s - aliased = %v; Expected: %v", row.TestCaseName, aliased, row.aliased)
+		} else {
+			t.Logf("SUCCESS: aliased = %v; Expected: %v", aliased, row.aliased)
+		}
+	}
+}
+
+func TestDecoderWithRawUnknown(t *testing.T) {
+	// setup
+	// CAT023 (unknown) + CAT034 + CAT247 (unknown)
+	input := "170009 c0 0836 02 1234 220014f6083602429b7110940028200094008000 f70008 c0 0836 02 12"
+	type dataTest struct {
+		TestCaseName string
+		decoder      *Decoder
+		categories   []uint8
+		raw          []bool
+		err          error
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "unknown category fails by default",
+			decoder:      NewDecoder(),
+			categories:   nil,
+			raw:          nil,
+			err:          ErrCategoryUnknown,
+		},
+		{
+			TestCaseName: "unknown categories kept raw",
+			decoder:      NewDecoder(WithRawUnknown()),
+			categories:   []uint8{23, 34, 247},
+			raw:          []bool{true, false, true},
+			err:          nil,
+		},
+		{
+			TestCaseName: "resolver without profile",
+			decoder: NewDecoder(WithRawUnknown(), WithResolver(func(category uint8, src DataSource, hasSource bool) (uap.StandardUAP, bool) {
+				return uap.StandardUAP{}, false
+			})),
+			categories: []uint8{23, 34, 247},
+			raw:        []bool{true, false, true},
+			err:        nil,
+		},
+	}
+
+	for _, row := range dataSet {
+		// Arrange
+		data, _ := util.HexStringToByte(input)
+
+		// Act
+		w, _, err := row.decoder.DecodeWrapper(data)
+
+		// Assert
+		if err != row.err || len(w.DataBlocks) != len(row.categories) {
+			t.Errorf("FAIL: %s - error = %v, nbOfDataBlocks = %v; Expected: %v, %v",
+				row.TestCaseName, err, len(w.DataBlocks), row.err, len(row.categories))
+			continue
+		}
+		if err != nil {
+			t.Logf("SUCCESS: %s - error = %v", row.TestCaseName, err)
+			continue
+		}
+		for i, db := range w.DataBlocks {
+			if db.Category != row.categories[i] || (db.Raw != nil) != row.raw[i] || (len(db.Records) == 0) != row.raw[i] {
+				t.Errorf("FAIL: %s - data block %d: CAT%03d, raw = %x; Expected: CAT%03d", row.TestCaseName, i, db.Category, db.Raw, row.categories[i])
+			}
+		}
+		encoded, err := w.Encode()
+		if err != nil || !bytes.Equal(encoded, data) {
+			t.Errorf("FAIL: %s - encoded = %x, error = %v; Expected: %x", row.TestCaseName, encoded, err, data)
+		}
+		packed, err := w.Pack(30)
+		if err != nil || !bytes.Equal(bytes.Join(packed, nil), data) || len(packed) != 2 {
+			t.Errorf("FAIL: %s - packed = %x, error = %v; Expected: %x", row.TestCaseName, packed, err, data)
+		} else {
+			t.Logf("SUCCESS: %s - data blocks = %v, raw = %v", row.TestCaseName, row.categories, row.raw)
+		}
+	}
+}
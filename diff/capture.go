@@ -0,0 +1,143 @@
+package diff
+
+import (
+	"github.com/mokhtarimokhtar/goasterix"
+)
+
+// Key identifies a record of a capture: its category, its Data Source Identifier (SAC/SIC), its track number
+// and its time of day (in 1/128 s, the raw value of the item).
+type Key struct {
+	Category    uint8
+	Sac         uint8
+	Sic         uint8
+	TrackNumber uint16
+	TimeOfDay   uint32
+}
+
+// KeyFunc returns the key of a record, false if the record has no key (e.g. a plot without track number).
+type KeyFunc func(rec *goasterix.Record) (Key, bool)
+
+// KeyItems are the names of the Fixed items of the key of a category (DataItem of the UAP):
+// the Data Source Identifier, the track number and the time of day.
+type KeyItems struct {
+	Source      string
+	TrackNumber string
+	TimeOfDay   string
+}
+
+// TrackItems are the items of the key of the track categories, used by TrackKey.
+var TrackItems = map[uint8]KeyItems{
+	48: {Source: "I048/010", TrackNumber: "I048/161", TimeOfDay: "I048/140"},
+	62: {Source: "I062/010", TrackNumber: "I062/040", TimeOfDay: "I062/070"},
+}
+
+// TrackKey returns the key of a record of a category of TrackItems, see ItemsKey.
+func TrackKey(rec *goasterix.Record) (Key, bool) {
+	items, found := TrackItems[rec.Cat]
+	if !found {
+		return Key{}, false
+	}
+	return ItemsKey(items)(rec)
+}
+
+// ItemsKey returns a KeyFunc which reads the key in the Fixed items named by items.
+// A record has no key if one of the items is absent or too large.
+func ItemsKey(items KeyItems) KeyFunc {
+	return func(rec *goasterix.Record) (Key, bool) {
+		source, err := rec.Fixed(items.Source)
+		if err != nil || len(source.Data) != 2 {
+			return Key{}, false
+		}
+		trackNumber, err := rec.Fixed(items.TrackNumber)
+		if err != nil || len(trackNumber.Data) > 2 {
+			return Key{}, false
+		}
+		timeOfDay, err := rec.Fixed(items.TimeOfDay)
+		if err != nil || len(timeOfDay.Data) > 4 {
+			return Key{}, false
+		}
+		return Key{
+			Category:    rec.Cat,
+			Sac:         source.Data[0],
+			Sic:         source.Data[1],
+			TrackNumber: uint16(unsigned(trackNumber.Data)),
+			TimeOfDay:   unsigned(timeOfDay.Data),
+		}, true
+	}
+}
+
+// unsigned returns the big-endian value of data.
+func unsigned(data []byte) uint32 {
+	var v uint32
+	for _, b := range data {
+		v = v<<8 | uint32(b)
+	}
+	return v
+}
+
+// Captures returns the records added, removed or changed between the captures old and new (e.g. the data blocks
+// of two recordings of the same traffic). The records are matched by their key (TrackKey if key is nil),
+// the records of the same key are matched in the order of the captures. The records without key are not compared.
+// The records removed and changed are returned in the order of old, followed by the records added in the order of new.
+// The items of the records changed can be compared through their transform models with a tolerance, see RecordModels.
+func Captures(old, new []*goasterix.DataBlock, key KeyFunc) []RecordDifference {
+	if key == nil {
+		key = TrackKey
+	}
+
+	newRecords := records(new)
+	matched := make([]bool, len(newRecords))
+	byKey := make(map[Key][]int)
+	for i, rec := range newRecords {
+		if k, found := key(rec); found {
+			byKey[k] = append(byKey[k], i)
+		}
+	}
+
+	var diffs []RecordDifference
+	for i, rec := range records(old) {
+		k, found := key(rec)
+		if !found {
+			continue
+		}
+		indexes := byKey[k]
+		if len(indexes) == 0 {
+			diffs = append(diffs, RecordDifference{Kind: Removed, Index: i, Key: k, Old: rec})
+			continue
+		}
+		j := indexes[0]
+		byKey[k] = indexes[1:]
+		matched[j] = true
+		if d := Records(rec, newRecords[j]); len(d) != 0 {
+			diffs = append(diffs, RecordDifference{
+				Kind:        Changed,
+				Index:       i,
+				Key:         k,
+				Old:         rec,
+				New:         newRecords[j],
+				Differences: d,
+			})
+		}
+	}
+
+	for j, rec := range newRecords {
+		if matched[j] {
+			continue
+		}
+		if k, found := key(rec); found {
+			diffs = append(diffs, RecordDifference{Kind: Added, Index: j, Key: k, New: rec})
+		}
+	}
+	return diffs
+}
+
+// records returns the records of the data blocks.
+func records(blocks []*goasterix.DataBlock) []*goasterix.Record {
+	var recs []*goasterix.Record
+	for _, db := range blocks {
+		if db != nil {
+			recs = append(recs, db.Records...)
+		}
+	}
+	return recs
+}
@@ -0,0 +1,120 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mokhtarimokhtar/goasterix"
+)
+
+func TestTrackKey(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		input        string
+		output       Key
+		found        bool
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "cat062 track",
+			input:        cat062Block,
+			output:       Key{Category: 62, Sac: 0x09, Sic: 0x00, TrackNumber: 0x04b2, TimeOfDay: 0x532100},
+			found:        true,
+		},
+		{
+			TestCaseName: "cat048 track",
+			input:        "30003a fff702 0836 429b52 a0 94c70181 0913 02d0 6002b7 490d01 38a178cf4220 02e79a5d27a00c0060a3280030a4000040 063a 0743ce5b 40 20f5",
+			output:       Key{Category: 48, Sac: 0x08, Sic: 0x36, TrackNumber: 0x063a, TimeOfDay: 0x429b52},
+			found:        true,
+		},
+		{
+			TestCaseName: "cat048 plot without track number",
+			input:        "30000e f0 0836 429b52 a0 94c70181",
+			output:       Key{},
+			found:        false,
+		},
+		{
+			TestCaseName: "category without key",
+			input:        "220014f6083602429b7110940028200094008000",
+			output:       Key{},
+			found:        false,
+		},
+	}
+
+	for _, row := range dataSet {
+		// Arrange
+		db := decodeBlock(t, row.input)
+
+		// Act
+		key, found := TrackKey(db.Records[0])
+
+		// Assert
+		if key != row.output || found != row.found {
+			t.Errorf("FAIL: %s - key = %+v, %v; Expected: %+v, %v", row.TestCaseName, key, found, row.output, row.found)
+		} else {
+			t.Logf("SUCCESS: %s - key = %+v, %v; Expected: %+v, %v", row.TestCaseName, key, found, row.output, row.found)
+		}
+	}
+}
+
+func TestCaptures(t *testing.T) {
+	// Arrange
+	// track 04b2 changed, track 002c renumbered 002d, track 0336 unchanged, a cat034 record without key
+	oldBlock := decodeBlock(t, cat062Block)
+	newBlock := decodeBlock(t, strings.Replace(strings.Replace(cat062Block, "87304a", "87304b", 1),
+		"005f002c19", "005f002d19", 1))
+	service := decodeBlock(t, "220014f6083602429b7110940028200094008000")
+	old := []*goasterix.DataBlock{service, oldBlock}
+	new := []*goasterix.DataBlock{newBlock, service}
+
+	key := Key{Category: 62, Sac: 0x09, Sic: 0x00, TimeOfDay: 0x532100}
+	type output struct {
+		kind  Kind
+		index int
+		track uint16
+		diffs []string
+	}
+	expected := []output{
+		{kind: Changed, index: 1, track: 0x04b2, diffs: []string{"changed I062/380/ADR: 87304a => 87304b"}},
+		{kind: Removed, index: 2, track: 0x002c},
+		{kind: Added, index: 1, track: 0x002d},
+	}
+
+	// Act
+	diffs := Captures(old, new, nil)
+
+	// Assert
+	if len(diffs) != len(expected) {
+		t.Fatalf("FAIL: %d records; Expected: %d", len(diffs), len(expected))
+	}
+	for i, e := range expected {
+		key.TrackNumber = e.track
+		d := diffs[i]
+		if d.Kind != e.kind || d.Index != e.index || d.Key != key || !equal(strs(d.Differences), e.diffs) {
+			t.Errorf("FAIL: %s %d %+v %v; Expected: %s %d %+v %v", d.Kind, d.Index, d.Key, strs(d.Differences),
+				e.kind, e.index, key, e.diffs)
+		} else {
+			t.Logf("SUCCESS: %s %d %+v %v", d.Kind, d.Index, d.Key, strs(d.Differences))
+		}
+	}
+}
+
+func TestCaptures_ItemsKey(t *testing.T) {
+	// Arrange
+	// with the key without track number, the tracks of the same time of day are matched in order
+	oldBlock := decodeBlock(t, cat062Block)
+	newBlock := decodeBlock(t, strings.Replace(cat062Block, "005f002c19", "005f002d19", 1))
+	key := ItemsKey(KeyItems{Source: "I062/010", TrackNumber: "I062/015", TimeOfDay: "I062/070"})
+
+	// Act
+	diffs := Captures([]*goasterix.DataBlock{oldBlock}, []*goasterix.DataBlock{newBlock}, key)
+
+	// Assert
+	if len(diffs) != 1 || diffs[0].Kind != Changed || diffs[0].Index != 1 ||
+		!equal(strs(diffs[0].Differences), []string{"changed I062/040: 002c => 002d"}) {
+		t.Errorf("FAIL: %v; Expected: record 1 changed", diffs)
+	} else {
+		t.Logf("SUCCESS: %v", strs(diffs[0].Differences))
+	}
+}
@@ -0,0 +1,213 @@
+// Package diff compares asterix records, data blocks and captures: it reports the items added, removed or changed
+// per FRN and per data subfield of the Compound items, and the fields of the transform models changed beyond
+// a numeric tolerance, e.g. to check the output of a new release of a tracker against a reference recording.
+package diff
+
+import (
+	"bytes"
+	"encoding/hex"
+	"sort"
+	"strconv"
+
+	"github.com/mokhtarimokhtar/goasterix"
+)
+
+// Kind is the kind of a difference.
+type Kind uint8
+
+const (
+	// Added is an item (or record) present only in the new record (or capture).
+	Added Kind = iota
+	// Removed is an item (or record) present only in the old record (or capture).
+	Removed
+	// Changed is an item (or record) present in both but different.
+	Changed
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Changed:
+		return "changed"
+	}
+	return "unknown"
+}
+
+// Difference is an item, or a data subfield of a Compound item (or of a RE or SP item with sub-profile),
+// which differs between two records.
+// Name is the name of the item (DataItem of the UAP) followed for a data subfield by "/" and its own name,
+// e.g. "I062/380/ADR" like goasterix.Record.Lookup. SubFRN is the FRN of the data subfield, 0 for an item.
+// Old and New are the payloads compared, Old is nil for an item added and New is nil for an item removed.
+type Difference struct {
+	Kind   Kind
+	FRN    uint8
+	SubFRN uint8
+	Name   string
+	Old    []byte
+	New    []byte
+}
+
+func (d Difference) String() string {
+	str := d.Kind.String() + " " + d.Name + ": "
+	switch d.Kind {
+	case Added:
+		str += hex.EncodeToString(d.New)
+	case Removed:
+		str += hex.EncodeToString(d.Old)
+	default:
+		str += hex.EncodeToString(d.Old) + " => " + hex.EncodeToString(d.New)
+	}
+	return str
+}
+
+// RecordDifference is a record added, removed or changed between two data blocks or captures.
+// Index is the position of the record in its data block (DataBlocks) or in the records of its capture (Captures):
+// the old record for a record removed or changed, the new one for a record added.
+// Key is the key which matches the records of two captures, see Captures.
+// Old is nil for a record added and New is nil for a record removed, Differences are the items of a record changed.
+type RecordDifference struct {
+	Kind        Kind
+	Index       int
+	Key         Key
+	Old         *goasterix.Record
+	New         *goasterix.Record
+	Differences []Difference
+}
+
+// Records returns the items added, removed or changed between the records old and new, in the order of the FRNs.
+// The items present in both are compared by payload, a Compound item (or RE or SP item with sub-profile)
+// is compared per data subfield. A nil record has no item, it returns nil if the records have the same items.
+func Records(old, new *goasterix.Record) []Difference {
+	var oldItems, newItems []goasterix.Item
+	if old != nil {
+		oldItems = old.Items
+	}
+	if new != nil {
+		newItems = new.Items
+	}
+
+	var diffs []Difference
+	merge(oldItems, newItems, func(frn uint8, o, n *goasterix.Item) {
+		diffs = compareItems(diffs, frn, o, n)
+	})
+	return diffs
+}
+
+// DataBlocks returns the records added, removed or changed between the data blocks old and new.
+// The records are matched by their position in the data blocks: the records beyond the end of the shortest data block
+// are added or removed. The Raw data of the data blocks of unknown category are not compared.
+func DataBlocks(old, new *goasterix.DataBlock) []RecordDifference {
+	var oldRecords, newRecords []*goasterix.Record
+	if old != nil {
+		oldRecords = old.Records
+	}
+	if new != nil {
+		newRecords = new.Records
+	}
+
+	var diffs []RecordDifference
+	for i := 0; i < len(oldRecords) || i < len(newRecords); i++ {
+		switch {
+		case i >= len(newRecords):
+			diffs = append(diffs, RecordDifference{Kind: Removed, Index: i, Old: oldRecords[i]})
+		case i >= len(oldRecords):
+			diffs = append(diffs, RecordDifference{Kind: Added, Index: i, New: newRecords[i]})
+		default:
+			if d := Records(oldRecords[i], newRecords[i]); len(d) != 0 {
+				diffs = append(diffs, RecordDifference{
+					Kind:        Changed,
+					Index:       i,
+					Old:         oldRecords[i],
+					New:         newRecords[i],
+					Differences: d,
+				})
+			}
+		}
+	}
+	return diffs
+}
+
+// compareItems appends the differences of the items frn o and n (nil if absent) to diffs.
+func compareItems(diffs []Difference, frn uint8, o, n *goasterix.Item) []Difference {
+	switch {
+	case o == nil:
+		return append(diffs, Difference{Kind: Added, FRN: frn, Name: itemName(n), New: n.Payload()})
+	case n == nil:
+		return append(diffs, Difference{Kind: Removed, FRN: frn, Name: itemName(o), Old: o.Payload()})
+	}
+
+	oldPayload, newPayload := o.Payload(), n.Payload()
+	if bytes.Equal(oldPayload, newPayload) {
+		return diffs
+	}
+	if oc, nc := compound(o), compound(n); oc != nil && nc != nil {
+		start := len(diffs)
+		name := itemName(n)
+		merge(oc.Secondary, nc.Secondary, func(subFRN uint8, os, ns *goasterix.Item) {
+			d := Difference{FRN: frn, SubFRN: subFRN}
+			switch {
+			case os == nil:
+				d.Kind, d.Name, d.New = Added, name+"/"+ns.Meta.DataItem, ns.Payload()
+			case ns == nil:
+				d.Kind, d.Name, d.Old = Removed, name+"/"+os.Meta.DataItem, os.Payload()
+			default:
+				d.Kind, d.Name, d.Old, d.New = Changed, name+"/"+ns.Meta.DataItem, os.Payload(), ns.Payload()
+				if bytes.Equal(d.Old, d.New) {
+					return
+				}
+			}
+			diffs = append(diffs, d)
+		})
+		if len(diffs) != start {
+			return diffs
+		}
+		// same data subfields: the difference is in the spare bits of the primary subfield
+	}
+	return append(diffs, Difference{Kind: Changed, FRN: frn, Name: itemName(n), Old: oldPayload, New: newPayload})
+}
+
+// merge calls fn for each FRN of the items of old or new in increasing order,
+// with the items of this FRN (nil if absent).
+func merge(old, new []goasterix.Item, fn func(frn uint8, o, n *goasterix.Item)) {
+	oldByFRN := make(map[uint8]*goasterix.Item, len(old))
+	newByFRN := make(map[uint8]*goasterix.Item, len(new))
+	frns := make([]uint8, 0, len(old)+len(new))
+	for i := range old {
+		oldByFRN[old[i].Meta.FRN] = &old[i]
+		frns = append(frns, old[i].Meta.FRN)
+	}
+	for i := range new {
+		frn := new[i].Meta.FRN
+		newByFRN[frn] = &new[i]
+		if _, found := oldByFRN[frn]; !found {
+			frns = append(frns, frn)
+		}
+	}
+	sort.Slice(frns, func(i, j int) bool { return frns[i] < frns[j] })
+
+	for _, frn := range frns {
+		fn(frn, oldByFRN[frn], newByFRN[frn])
+	}
+}
+
+// compound returns the data subfields of a Compound item or of a RE or SP item with sub-profile, nil otherwise.
+func compound(item *goasterix.Item) *goasterix.Compound {
+	if item.Compound != nil {
+		return item.Compound
+	}
+	if item.SP != nil {
+		return item.SP.Compound
+	}
+	return nil
+}
+
+// itemName returns the name of the item, "FRN" and its number if the UAP does not name it.
+func itemName(item *goasterix.Item) string {
+	if item.Meta.DataItem != "" {
+		return item.Meta.DataItem
+	}
+	return "FRN " + strconv.Itoa(int(item.Meta.FRN))
+}
@@ -0,0 +1,179 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mokhtarimokhtar/goasterix"
+	"github.com/mokhtarimokhtar/goasterix/util"
+)
+
+// cat062Block is a cat062 data block of 3 tracks (04b2, 002c, 0336) with the same time of day
+const cat062Block = "3e0186bf5ffd0304090001532100008e6f3e0017d0961247f10b7086fed3019a0fc8e301010c87304a04e072c34820e300820800eb003104b2190301487fa0ff0614ffffffffffff0493110101c006061414141400e0045b00e00182dc622931a410a800e00fc84010e001622b05010d01622902fea60177bf5ffd0304090001532100008f45be000478e9036aa20b78f8fdbc023c0f55e301010c40123f0815f5cf1820dee002d0010f005f002c190301087fa02a0707ffffffffffff0893110101c0070707070707051f13c5051bfdd4dc085066b0f616051f0f55a02aa0070814050221060b0500b108360502ea0813050761060a05056808090504340850050236fdb10230bf5ffd0304090001532100008ea9d100149a720fcb720b75af033a014d0baae301010c780de50c54f7c39e202bc003c0012b00560336190301087fa00e0606ffffffffffff0493110101c0060606060606039715f00399013e98060a03970baa1fe00408120501f6080a05065f06030701f4060a0503b10162290203180199"
+
+// decodeBlock returns the data block of the hex string s.
+func decodeBlock(t *testing.T, s string) *goasterix.DataBlock {
+	t.Helper()
+	data, _ := util.HexStringToByte(s)
+	db := goasterix.NewDataBlock()
+	if _, err := db.Decode(data); err != nil {
+		t.Fatalf("FAIL: decode %s: %v", s, err)
+	}
+	return db
+}
+
+// strs returns the strings of the differences.
+func strs(diffs []Difference) []string {
+	var res []string
+	for _, d := range diffs {
+		res = append(res, d.String())
+	}
+	return res
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRecords(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		input        string // new data block, compared with cat062Block
+		output       []string
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "same records",
+			input:        cat062Block,
+			output:       nil,
+		},
+		{
+			TestCaseName: "SAC/SIC changed",
+			input:        strings.Replace(cat062Block, "bf5ffd03040900", "bf5ffd03040102", 1),
+			output:       []string{"changed I062/010: 0900 => 0102"},
+		},
+		{
+			TestCaseName: "data subfield changed",
+			input:        strings.Replace(cat062Block, "87304a", "87304b", 1),
+			output:       []string{"changed I062/380/ADR: 87304a => 87304b"},
+		},
+		{
+			TestCaseName: "data subfield removed",
+			input:        strings.Replace(strings.Replace(cat062Block, "e301010c87304a", "6301010c", 1), "3e0186", "3e0183", 1),
+			output:       []string{"removed I062/380/ADR: 87304a"},
+		},
+		{
+			TestCaseName: "item removed",
+			input: strings.Replace(strings.Replace(strings.Replace(cat062Block, "bf5ffd0304", "bf57fd0304", 1),
+				"eb003104b219", "eb003119", 1), "3e0186", "3e0184", 1),
+			output: []string{"removed I062/040: 04b2"},
+		},
+		{
+			TestCaseName: "several items changed",
+			input: strings.Replace(strings.Replace(cat062Block, "0fc8e301", "0fc9e301", 1),
+				"00e0045b00e0", "00e1045b00e0", 1),
+			output: []string{"changed I062/060: 0fc8 => 0fc9", "changed I062/136: 00e0 => 00e1"},
+		},
+	}
+
+	for _, row := range dataSet {
+		// Arrange
+		old := decodeBlock(t, cat062Block)
+		new := decodeBlock(t, row.input)
+
+		// Act
+		diffs := Records(old.Records[0], new.Records[0])
+
+		// Assert
+		if !equal(strs(diffs), row.output) {
+			t.Errorf("FAIL: %s - diffs = %v; Expected: %v", row.TestCaseName, strs(diffs), row.output)
+		} else {
+			t.Logf("SUCCESS: %s - diffs = %v; Expected: %v", row.TestCaseName, strs(diffs), row.output)
+		}
+	}
+}
+
+func TestRecords_Nil(t *testing.T) {
+	// Arrange
+	db := decodeBlock(t, cat062Block)
+	rec := db.Records[0]
+
+	// Act
+	added := Records(nil, rec)
+	removed := Records(rec, nil)
+
+	// Assert
+	if len(added) != len(rec.Items) || len(removed) != len(rec.Items) {
+		t.Errorf("FAIL: added = %d, removed = %d; Expected: %d", len(added), len(removed), len(rec.Items))
+		return
+	}
+	for i := range rec.Items {
+		if added[i].Kind != Added || removed[i].Kind != Removed || added[i].FRN != rec.Items[i].Meta.FRN {
+			t.Errorf("FAIL: %v, %v; Expected: item %s added and removed", added[i], removed[i], rec.Items[i].Meta.DataItem)
+			return
+		}
+	}
+	t.Logf("SUCCESS: %d items added and removed", len(rec.Items))
+}
+
+func TestDataBlocks(t *testing.T) {
+	// Arrange
+	old := decodeBlock(t, cat062Block)
+	new := decodeBlock(t, strings.Replace(cat062Block, "005f002c19", "005f002d19", 1))
+	new.Records = new.Records[:2]
+
+	// Act
+	diffs := DataBlocks(old, new)
+
+	// Assert
+	if len(diffs) != 2 {
+		t.Fatalf("FAIL: %d records; Expected: 2", len(diffs))
+	}
+	if diffs[0].Kind != Changed || diffs[0].Index != 1 || diffs[0].Old != old.Records[1] || diffs[0].New != new.Records[1] ||
+		!equal(strs(diffs[0].Differences), []string{"changed I062/040: 002c => 002d"}) {
+		t.Errorf("FAIL: %v; Expected: record 1 changed", diffs[0])
+	} else {
+		t.Logf("SUCCESS: record 1 changed: %v", strs(diffs[0].Differences))
+	}
+	if diffs[1].Kind != Removed || diffs[1].Index != 2 || diffs[1].Old != old.Records[2] || diffs[1].New != nil {
+		t.Errorf("FAIL: %v; Expected: record 2 removed", diffs[1])
+	} else {
+		t.Logf("SUCCESS: record 2 removed")
+	}
+}
+
+func TestKind_String(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		input        Kind
+		output       string
+	}
+	dataSet := []dataTest{
+		{TestCaseName: "added", input: Added, output: "added"},
+		{TestCaseName: "removed", input: Removed, output: "removed"},
+		{TestCaseName: "changed", input: Changed, output: "changed"},
+		{TestCaseName: "unknown", input: Kind(3), output: "unknown"},
+	}
+
+	for _, row := range dataSet {
+		// Act
+		s := row.input.String()
+
+		// Assert
+		if s != row.output {
+			t.Errorf("FAIL: %s - %s; Expected: %s", row.TestCaseName, s, row.output)
+		} else {
+			t.Logf("SUCCESS: %s - %s; Expected: %s", row.TestCaseName, s, row.output)
+		}
+	}
+}
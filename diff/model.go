@@ -0,0 +1,184 @@
+package diff
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/mokhtarimokhtar/goasterix"
+	"github.com/mokhtarimokhtar/goasterix/transform"
+)
+
+// Tolerances are the maximum absolute differences of the numeric fields of transform models considered as equal,
+// by path of the field: the JSON names of the fields from the model separated by ".", without the indexes
+// of the slices, e.g. "trackPositionWGS84.latitude" for a Cat062Model. The fields without tolerance are compared exactly.
+type Tolerances map[string]float64
+
+// FieldDifference is a field of a transform model added, removed or changed between two models.
+// Path is the path of the field (see Tolerances) with the indexes of the slices, e.g. "aircraftDerivedData.magneticHeading".
+// Old and New are the values compared, Old is nil for a field added and New is nil for a field removed
+// (a nil pointer or a slice shorter than the other).
+type FieldDifference struct {
+	Kind Kind
+	Path string
+	Old  interface{}
+	New  interface{}
+}
+
+func (d FieldDifference) String() string {
+	switch d.Kind {
+	case Added:
+		return fmt.Sprintf("%s %s: %v", d.Kind, d.Path, d.New)
+	case Removed:
+		return fmt.Sprintf("%s %s: %v", d.Kind, d.Path, d.Old)
+	}
+	return fmt.Sprintf("%s %s: %v => %v", d.Kind, d.Path, d.Old, d.New)
+}
+
+// Models returns the fields added, removed or changed between two transform models of the same type
+// (e.g. *transform.Cat062Model), field by field in the order of their declaration.
+// The numeric fields are changed if they differ by more than their tolerance (tol can be nil).
+// Models of different types are changed as a whole, with an empty Path.
+func Models(old, new interface{}, tol Tolerances) []FieldDifference {
+	c := comparator{tol: tol}
+	a, b := reflect.ValueOf(old), reflect.ValueOf(new)
+	if a.IsValid() && b.IsValid() && a.Type() != b.Type() {
+		return []FieldDifference{{Kind: Changed, Old: old, New: new}}
+	}
+	c.compare("", "", a, b)
+	return c.diffs
+}
+
+// RecordModels compares two records through their transform models with the tolerances tol, see Models.
+// newModel returns an empty model of the category of the records, e.g.
+// func() transform.Writer { return new(transform.Cat062Model) }. A nil record gives an empty model.
+func RecordModels(old, new *goasterix.Record, newModel func() transform.Writer, tol Tolerances) []FieldDifference {
+	oldModel, newM := newModel(), newModel()
+	if old != nil {
+		transform.WriteModel(oldModel, *old)
+	}
+	if new != nil {
+		transform.WriteModel(newM, *new)
+	}
+	return Models(oldModel, newM, tol)
+}
+
+// comparator walks two values of the same type and collects their differences.
+type comparator struct {
+	tol   Tolerances
+	diffs []FieldDifference
+}
+
+// compare compares the values a and b of the field path, field is the path without the indexes of the slices.
+func (c *comparator) compare(path, field string, a, b reflect.Value) {
+	if !a.IsValid() || !b.IsValid() {
+		c.nilable(path, a, b)
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			c.nilable(path, a, b)
+			return
+		}
+		if a.Elem().Type() != b.Elem().Type() {
+			c.diffs = append(c.diffs, FieldDifference{Kind: Changed, Path: path, Old: a.Interface(), New: b.Interface()})
+			return
+		}
+		c.compare(path, field, a.Elem(), b.Elem())
+
+	case reflect.Struct:
+		t := a.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name := fieldName(f)
+			if name == "" {
+				continue
+			}
+			c.compare(join(path, name), join(field, name), a.Field(i), b.Field(i))
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < a.Len() || i < b.Len(); i++ {
+			elem := path + "[" + strconv.Itoa(i) + "]"
+			switch {
+			case i >= b.Len():
+				c.diffs = append(c.diffs, FieldDifference{Kind: Removed, Path: elem, Old: a.Index(i).Interface()})
+			case i >= a.Len():
+				c.diffs = append(c.diffs, FieldDifference{Kind: Added, Path: elem, New: b.Index(i).Interface()})
+			default:
+				c.compare(elem, field, a.Index(i), b.Index(i))
+			}
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		c.number(path, field, float64(a.Int()), float64(b.Int()), a, b)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		c.number(path, field, float64(a.Uint()), float64(b.Uint()), a, b)
+
+	case reflect.Float32, reflect.Float64:
+		c.number(path, field, a.Float(), b.Float(), a, b)
+
+	default:
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			c.diffs = append(c.diffs, FieldDifference{Kind: Changed, Path: path, Old: a.Interface(), New: b.Interface()})
+		}
+	}
+}
+
+// number compares the numeric values x and y of a and b with the tolerance of field.
+func (c *comparator) number(path, field string, x, y float64, a, b reflect.Value) {
+	if x == y || math.Abs(x-y) <= c.tol[field] {
+		return
+	}
+	c.diffs = append(c.diffs, FieldDifference{Kind: Changed, Path: path, Old: a.Interface(), New: b.Interface()})
+}
+
+// nilable compares a nil pointer (or invalid value) with another value.
+func (c *comparator) nilable(path string, a, b reflect.Value) {
+	aNil, bNil := isNil(a), isNil(b)
+	switch {
+	case aNil && bNil:
+	case aNil:
+		c.diffs = append(c.diffs, FieldDifference{Kind: Added, Path: path, New: b.Interface()})
+	case bNil:
+		c.diffs = append(c.diffs, FieldDifference{Kind: Removed, Path: path, Old: a.Interface()})
+	}
+}
+
+func isNil(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// fieldName returns the JSON name of a field, its Go name if it has no JSON name and "" if it is not encoded.
+func fieldName(f reflect.StructField) string {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name := strings.Split(tag, ",")[0]; name != "" {
+		return name
+	}
+	return f.Name
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,133 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mokhtarimokhtar/goasterix/transform"
+)
+
+func fieldStrs(diffs []FieldDifference) []string {
+	var res []string
+	for _, d := range diffs {
+		res = append(res, d.String())
+	}
+	return res
+}
+
+// history is a model with a slice
+type history struct {
+	Altitudes []float64 `json:"altitudes"`
+}
+
+func TestModels(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		old          interface{}
+		new          interface{}
+		tol          Tolerances
+		output       []string
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "same models",
+			old:          &transform.Cat062Model{TrackNumber: 1202, TimeOfDay: 42562},
+			new:          &transform.Cat062Model{TrackNumber: 1202, TimeOfDay: 42562},
+			tol:          nil,
+			output:       nil,
+		},
+		{
+			TestCaseName: "numeric fields changed",
+			old:          &transform.Cat062Model{TrackNumber: 1202, TimeOfDay: 42562},
+			new:          &transform.Cat062Model{TrackNumber: 1203, TimeOfDay: 42562.5},
+			tol:          nil,
+			output:       []string{"changed timeOfDay: 42562 => 42562.5", "changed trackNumber: 1202 => 1203"},
+		},
+		{
+			TestCaseName: "numeric field within tolerance",
+			old:          &transform.Cat062Model{TrackNumber: 1202, TimeOfDay: 42562},
+			new:          &transform.Cat062Model{TrackNumber: 1203, TimeOfDay: 42562.5},
+			tol:          Tolerances{"timeOfDay": 1, "trackNumber": 0.5},
+			output:       []string{"changed trackNumber: 1202 => 1203"},
+		},
+		{
+			TestCaseName: "nested fields",
+			old: &transform.Cat062Model{
+				SacSic:             &transform.SourceIdentifier{Sac: 9, Sic: 0},
+				TrackPositionWGS84: &transform.PositionWGS84{Latitude: 43.5, Longitude: 1.25},
+			},
+			new: &transform.Cat062Model{
+				SacSic:             &transform.SourceIdentifier{Sac: 9, Sic: 1},
+				TrackPositionWGS84: &transform.PositionWGS84{Latitude: 43.50001, Longitude: 1.25002},
+			},
+			tol:    Tolerances{"trackPositionWGS84.latitude": 0.0001},
+			output: []string{"changed sourceIdentifier.sic: 0 => 1", "changed trackPositionWGS84.longitude: 1.25 => 1.25002"},
+		},
+		{
+			TestCaseName: "pointer fields added and removed",
+			old:          &transform.Cat062Model{Mode3ACode: &transform.TrackMode3A{Squawk: "1000"}},
+			new:          &transform.Cat062Model{TrackVelocity: &transform.TrackVelocity{Vx: 1}},
+			tol:          nil,
+			output:       []string{"added trackVelocity: &{1 0}", "removed mode3ACode: &{   1000}"},
+		},
+		{
+			TestCaseName: "slices",
+			old:          &history{Altitudes: []float64{100, 200}},
+			new:          &history{Altitudes: []float64{100.2, 250, 300}},
+			tol:          Tolerances{"altitudes": 1},
+			output:       []string{"changed altitudes[1]: 200 => 250", "added altitudes[2]: 300"},
+		},
+		{
+			TestCaseName: "different types",
+			old:          &history{Altitudes: []float64{100}},
+			new:          &transform.SourceIdentifier{Sac: 9},
+			tol:          nil,
+			output:       []string{"changed : &{[100]} => &{9 0}"},
+		},
+	}
+
+	for _, row := range dataSet {
+		// Act
+		diffs := Models(row.old, row.new, row.tol)
+
+		// Assert
+		res := fieldStrs(diffs)
+		if !equal(res, row.output) {
+			t.Errorf("FAIL: %s - diffs = %v; Expected: %v", row.TestCaseName, res, row.output)
+		} else {
+			t.Logf("SUCCESS: %s - diffs = %v; Expected: %v", row.TestCaseName, res, row.output)
+		}
+	}
+}
+
+func TestRecordModels(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		tol          Tolerances
+		output       int
+	}
+	// the latitude of the track 04b2 is moved of 1 LSB (180/2^25 degree)
+	newModel := func() transform.Writer { return new(transform.Cat062Model) }
+	dataSet := []dataTest{
+		{TestCaseName: "without tolerance", tol: nil, output: 1},
+		{TestCaseName: "latitude tolerance", tol: Tolerances{"trackPositionWGS84.latitude": 1e-5}, output: 0},
+	}
+
+	for _, row := range dataSet {
+		// Arrange
+		old := decodeBlock(t, cat062Block)
+		new := decodeBlock(t, strings.Replace(cat062Block, "008e6f3e0017d096", "008e6f3f0017d096", 1))
+
+		// Act
+		diffs := RecordModels(old.Records[0], new.Records[0], newModel, row.tol)
+
+		// Assert
+		if len(diffs) != row.output || (len(diffs) == 1 && diffs[0].Path != "trackPositionWGS84.latitude") {
+			t.Errorf("FAIL: %s - diffs = %v; Expected: %d", row.TestCaseName, fieldStrs(diffs), row.output)
+		} else {
+			t.Logf("SUCCESS: %s - diffs = %v; Expected: %d", row.TestCaseName, fieldStrs(diffs), row.output)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package goasterix
+
+import (
+	"github.com/mokhtarimokhtar/goasterix/uap"
+)
+
+// Set adds the item frn to the record or replaces it, e.g. to replace the Data Source Identifier before
+// forwarding the record. The item is checked against the UAP of the record and its computed fields are set
+// like Encode (FX bits, length of Explicit items, REP factor, primary subfield of Compound items).
+// The FSPEC is recomputed and the LEN of the data block of the record is updated: Payload stays valid.
+// The UAP of the record is the one of its decoding or encoding, else the default profile of its category.
+// The record is unchanged if it returns an error.
+func (rec *Record) Set(frn uint8, item Item) error {
+	p, err := rec.recordPlan()
+	if err != nil {
+		return err
+	}
+	fields, err := rec.fields(p)
+	if err != nil {
+		return err
+	}
+	if frn == 0 || int(frn) > len(fields) || fields[frn-1].field.FRN != frn {
+		return ErrFRNUnknown
+	}
+	enc, err := encodeItem(item, fields[frn-1].field, dataFields(fields))
+	if err != nil {
+		return err
+	}
+
+	items := make([]Item, 0, len(rec.Items)+1)
+	inserted := false
+	for _, tmp := range rec.Items {
+		if !inserted && tmp.Meta.FRN >= frn {
+			items = append(items, enc)
+			inserted = true
+		}
+		if tmp.Meta.FRN != frn {
+			items = append(items, tmp)
+		}
+	}
+	if !inserted {
+		items = append(items, enc)
+	}
+	return rec.update(items, p)
+}
+
+// Replace replaces the item frn like Set, it returns ErrItemNotFound if the item is absent.
+func (rec *Record) Replace(frn uint8, item Item) error {
+	if !rec.Has(frn) {
+		return ErrItemNotFound
+	}
+	return rec.Set(frn, item)
+}
+
+// Delete removes the item frn of the record, e.g. to strip the Aircraft Address before forwarding the record.
+// The FSPEC and the LEN of the data block are updated like Set. It returns ErrItemNotFound if the item is absent
+// and ErrRecordEmpty if it is the last item of the record.
+func (rec *Record) Delete(frn uint8) error {
+	if !rec.Has(frn) {
+		return ErrItemNotFound
+	}
+	p, err := rec.recordPlan()
+	if err != nil {
+		return err
+	}
+	items := make([]Item, 0, len(rec.Items))
+	for _, tmp := range rec.Items {
+		if tmp.Meta.FRN != frn {
+			items = append(items, tmp)
+		}
+	}
+	return rec.update(items, p)
+}
+
+// SetItem adds or replaces the item named dataItem (DataItem of the UAP), see Set.
+func (rec *Record) SetItem(dataItem string, item Item) error {
+	p, err := rec.recordPlan()
+	if err != nil {
+		return err
+	}
+	fields, err := rec.fields(p)
+	if err != nil {
+		return err
+	}
+	for _, pf := range fields {
+		if pf.field.DataItem == dataItem {
+			return rec.Set(pf.field.FRN, item)
+		}
+	}
+	return ErrItemNotFound
+}
+
+// ReplaceItem replaces the item named dataItem, see Replace.
+func (rec *Record) ReplaceItem(dataItem string, item Item) error {
+	if !rec.HasItem(dataItem) {
+		return ErrItemNotFound
+	}
+	return rec.SetItem(dataItem, item)
+}
+
+// DeleteItem removes the item named dataItem, see Delete. The data subfields of a Compound item can not be deleted,
+// the Compound item is replaced instead.
+func (rec *Record) DeleteItem(dataItem string) error {
+	for _, tmp := range rec.Items {
+		if tmp.Meta.DataItem == dataItem {
+			return rec.Delete(tmp.Meta.FRN)
+		}
+	}
+	return ErrItemNotFound
+}
+
+// recordPlan returns the compiled UAP of the record.
+func (rec *Record) recordPlan() (*Plan, error) {
+	if rec.plan != nil {
+		return rec.plan, nil
+	}
+	stdUAP, found := defaultDecoder.profiles[rec.Cat]
+	if !found {
+		return nil, ErrCategoryUnknown
+	}
+	return profilePlan(stdUAP), nil
+}
+
+// fields returns the compiled data fields of the record: the variant selected by the discriminating item
+// of a conditional UAP, if present.
+func (rec *Record) fields(p *Plan) ([]planField, error) {
+	cond := p.stdUAP.Condition
+	if cond == nil {
+		return p.items, nil
+	}
+	item, err := rec.Item(cond.FRN)
+	if err != nil {
+		return p.items, nil
+	}
+	fields, found := p.variant(item.Payload())
+	if !found {
+		return nil, ErrConditionUnknown
+	}
+	return fields, nil
+}
+
+// dataFields returns the definitions of the compiled data fields.
+func dataFields(pfs []planField) []uap.DataField {
+	fields := make([]uap.DataField, len(pfs))
+	for i := range pfs {
+		fields[i] = pfs[i].field
+	}
+	return fields
+}
+
+// update replaces the items of the record by items, ordered by FRN, after checking that the record can be decoded.
+func (rec *Record) update(items []Item, p *Plan) error {
+	if len(items) == 0 {
+		return ErrRecordEmpty
+	}
+	frnIndex := make([]uint8, 0, len(items))
+	for _, item := range items {
+		frnIndex = append(frnIndex, item.Meta.FRN)
+	}
+	tmp := Record{Cat: rec.Cat, Fspec: FspecFromIndex(frnIndex), Items: items}
+	payload := tmp.Payload()
+
+	// the payload of the record must be decoded with its UAP, e.g. a change of the discriminating item
+	// of a conditional UAP must keep the following items valid
+	check := NewRecord()
+	unRead, _, err := check.decode(payload, p, false)
+	if err != nil {
+		return err
+	}
+	if unRead != 0 {
+		return ErrItemInvalid
+	}
+
+	if rec.block != nil {
+		length := int(rec.block.Len) + len(payload) - len(rec.Payload())
+		if length > 0xffff {
+			return ErrOversized
+		}
+		rec.block.Len = uint16(length)
+	}
+	rec.plan = p
+	rec.Fspec = tmp.Fspec
+	rec.Items = items
+	return nil
+}
@@ -0,0 +1,170 @@
+package goasterix
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/mokhtarimokhtar/goasterix/uap"
+	"github.com/mokhtarimokhtar/goasterix/util"
+)
+
+func TestRecordEdit(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		input        string
+		edit         func(rec *Record) error
+		output       string // data block after the edit of the first record
+		err          error
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "replace SAC/SIC by name",
+			input:        "220014f6083602429b7110940028200094008000",
+			edit: func(rec *Record) error {
+				return rec.ReplaceItem("I034/010", Item{Fixed: &Fixed{Data: []byte{0x01, 0x02}}})
+			},
+			output: "220014f6010202429b7110940028200094008000",
+			err:    nil,
+		},
+		{
+			TestCaseName: "add an item",
+			input:        "220014f6083602429b7110940028200094008000",
+			edit: func(rec *Record) error {
+				return rec.Set(5, Item{Fixed: &Fixed{Data: []byte{0xab, 0xcd}}})
+			},
+			output: "220016fe083602429b7110abcd940028200094008000",
+			err:    nil,
+		},
+		{
+			TestCaseName: "delete an item",
+			input:        "220014f6083602429b7110940028200094008000",
+			edit: func(rec *Record) error {
+				return rec.DeleteItem("I034/030")
+			},
+			output: "220011d6083602109400282000940080 00",
+			err:    nil,
+		},
+		{
+			TestCaseName: "strip the aircraft address",
+			input:        "30003a fff702 0836 429b52 a0 94c70181 0913 02d0 6002b7 490d01 38a178cf4220 02e79a5d27a00c0060a3280030a4000040 063a 0743ce5b 40 20f5",
+			edit: func(rec *Record) error {
+				return rec.DeleteItem("I048/220")
+			},
+			output: "300037 ff7702 0836 429b52 a0 94c70181 0913 02d0 6002b7 38a178cf4220 02e79a5d27a00c0060a3280030a4000040 063a 0743ce5b 40 20f5",
+			err:    nil,
+		},
+		{
+			TestCaseName: "delete the last octet of the FSPEC",
+			input:        "30003a fff702 0836 429b52 a0 94c70181 0913 02d0 6002b7 490d01 38a178cf4220 02e79a5d27a00c0060a3280030a4000040 063a 0743ce5b 40 20f5",
+			edit: func(rec *Record) error {
+				return rec.Delete(21)
+			},
+			output: "300037 fff6 0836 429b52 a0 94c70181 0913 02d0 6002b7 490d01 38a178cf4220 02e79a5d27a00c0060a3280030a4000040 063a 0743ce5b 40",
+			err:    nil,
+		},
+		{
+			TestCaseName: "item of wrong size",
+			input:        "220014f6083602429b7110940028200094008000",
+			edit: func(rec *Record) error {
+				return rec.Set(1, Item{Fixed: &Fixed{Data: []byte{0x01}}})
+			},
+			output: "220014f6083602429b7110940028200094008000",
+			err:    ErrItemInvalid,
+		},
+		{
+			TestCaseName: "replace an absent item",
+			input:        "220014f6083602429b7110940028200094008000",
+			edit: func(rec *Record) error {
+				return rec.Replace(5, Item{Fixed: &Fixed{Data: []byte{0xab, 0xcd}}})
+			},
+			output: "220014f6083602429b7110940028200094008000",
+			err:    ErrItemNotFound,
+		},
+		{
+			TestCaseName: "FRN unknown",
+			input:        "220014f6083602429b7110940028200094008000",
+			edit: func(rec *Record) error {
+				return rec.Set(20, Item{Fixed: &Fixed{Data: []byte{0xab}}})
+			},
+			output: "220014f6083602429b7110940028200094008000",
+			err:    ErrFRNUnknown,
+		},
+		{
+			TestCaseName: "delete the only item",
+			input:        "220006800836",
+			edit: func(rec *Record) error {
+				return rec.Delete(1)
+			},
+			output: "220006800836",
+			err:    ErrRecordEmpty,
+		},
+	}
+
+	for _, row := range dataSet {
+		// Arrange
+		data, _ := util.HexStringToByte(row.input)
+		expected, _ := util.HexStringToByte(row.output)
+		db := NewDataBlock()
+		if _, err := db.Decode(data); err != nil {
+			t.Fatalf("FAIL: %s - error = %v; Expected: %v", row.TestCaseName, err, nil)
+		}
+
+		// Act
+		err := row.edit(db.Records[0])
+
+		// Assert
+		output := bytes.Join(db.Payload(), nil)
+		if !errors.Is(err, row.err) || !bytes.Equal(output, expected) {
+			t.Errorf("FAIL: %s - error = %v, data block = %x; Expected: %v, %x", row.TestCaseName, err, output, row.err, expected)
+			continue
+		}
+		if _, err := NewDataBlock().Decode(output); err != nil {
+			t.Errorf("FAIL: %s - data block edited: error = %v; Expected: %v", row.TestCaseName, err, nil)
+		} else {
+			t.Logf("SUCCESS: %s - data block = %x, error = %v", row.TestCaseName, output, err)
+		}
+	}
+}
+
+func TestRecordEdit_ConditionRFS(t *testing.T) {
+	// Arrange
+	// CAT001 track: the RFS item (FRN 21) and the item of its sequence (FRN 3, I001/161) belong to the track variant
+	data, _ := util.HexStringToByte("f502 0831 98 01bf 0a1ebb43 022538e2 00")
+	rec := NewRecord()
+	_, _ = rec.Decode(data, uap.Cat001V12)
+	rfs := Item{RFS: &RandomFieldSequencing{Sequence: []RandomField{
+		{FRN: 3, Field: Item{Fixed: &Fixed{Data: []byte{0x01, 0xc0}}}},
+	}}}
+	output, _ := util.HexStringToByte("f50302 0831 98 01bf 0a1ebb43 022538e2 00 01 03 01c0")
+
+	// Act
+	err := rec.Set(21, rfs)
+
+	// Assert
+	if err != nil || !bytes.Equal(rec.Payload(), output) {
+		t.Errorf("FAIL: error = %v, record = %x; Expected: %v, %x", err, rec.Payload(), nil, output)
+	} else {
+		t.Logf("SUCCESS: record = %x", rec.Payload())
+	}
+}
+
+func TestRecordEdit_Condition(t *testing.T) {
+	// Arrange
+	// FRN 10 discriminates the variants of Cat4Test: the plot variant does not match the items of the track variant
+	data, _ := util.HexStringToByte("01 38 80ff ffff")
+	rec := NewRecord()
+	_, _ = rec.Decode(data, uap.Cat4Test)
+
+	// Act
+	errVariant := rec.Set(10, Item{Fixed: &Fixed{Data: []byte{0x00}}})
+	errTrack := rec.Set(11, Item{Fixed: &Fixed{Data: []byte{0x12}}})
+
+	// Assert
+	if errVariant == nil || errTrack != nil || !bytes.Equal(rec.Payload(), []byte{0x01, 0x38, 0x80, 0x12, 0xff, 0xff}) {
+		t.Errorf("FAIL: errors = %v, %v, record = %x; Expected: error, %v, 013880 12ffff", errVariant, errTrack, rec.Payload(), nil)
+	} else {
+		t.Logf("SUCCESS: errors = %v, %v, record = %x", errVariant, errTrack, rec.Payload())
+	}
+}
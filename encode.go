@@ -0,0 +1,326 @@
+package goasterix
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/mokhtarimokhtar/goasterix/uap"
+)
+
+var (
+	// ErrFRNUnknown reports that a FRN is not defined by the User Application Profile.
+	ErrFRNUnknown = errors.New("[ASTERIX] FRN unknown in UAP")
+
+	// ErrItemInvalid reports that an item does not match its definition in the User Application Profile.
+	ErrItemInvalid = errors.New("[ASTERIX] item does not match UAP definition")
+
+	// ErrRecordEmpty reports that a record does not contain any item.
+	ErrRecordEmpty = errors.New("[ASTERIX] record without item")
+
+	// ErrOversized reports that the encoded data exceeds the maximum size allowed by a length field.
+	ErrOversized = errors.New("[ASTERIX] oversized packet")
+
+	// ErrCategoryMismatch reports that a record does not belong to the category of its data block.
+	ErrCategoryMismatch = errors.New("[ASTERIX] record category mismatch")
+)
+
+// Encode builds a Record from a set of items keyed by FRN according to the User Application Profile.
+// The FSPEC, the FX bits of Extended items, the length of Explicit, RE and SP items, the REP factor of Repetitive
+// items and the primary subfield of Compound items (and of RE and SP items with sub-profile) are computed,
+// the Meta of each item is taken from the UAP.
+// It returns the record in byte: FSPEC + items ordered by FRN.
+func (rec *Record) Encode(items map[uint8]Item, stdUAP uap.StandardUAP) ([]byte, error) {
+	rec.Cat = stdUAP.Category
+	rec.Fspec = nil
+	rec.Items = nil
+	rec.plan = profilePlan(stdUAP)
+	stdUAP.Condition = stdUAP.EffectiveCondition()
+
+	if len(items) == 0 {
+		return nil, ErrRecordEmpty
+	}
+
+	frnIndex := make([]uint8, 0, len(items))
+	for frn := range items {
+		frnIndex = append(frnIndex, frn)
+	}
+	sort.Slice(frnIndex, func(i, j int) bool { return frnIndex[i] < frnIndex[j] })
+
+	fields := stdUAP.Items
+
+	for _, frn := range frnIndex {
+		if frn == 0 || int(frn) > len(fields) {
+			return nil, ErrFRNUnknown
+		}
+		uapItem := fields[frn-1]
+		if uapItem.FRN != frn {
+			return nil, ErrFRNUnknown
+		}
+
+		item, err := encodeItem(items[frn], expansionField(uapItem, stdUAP), fields)
+		if err != nil {
+			return nil, err
+		}
+		rec.Items = append(rec.Items, item)
+
+		if stdUAP.Condition != nil && frn == stdUAP.Condition.FRN {
+			fields, err = conditionalFields(stdUAP, &item)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	rec.Fspec = FspecFromIndex(frnIndex)
+
+	return rec.Payload(), nil
+}
+
+// encodeItem checks an item against its UAP definition and returns a copy with the Meta and the computed fields set.
+// fields is passed to encodeRFS for a RFS item.
+func encodeItem(item Item, field uap.DataField, fields []uap.DataField) (Item, error) {
+	enc := *NewItem(field)
+
+	switch field.Type {
+	case uap.Fixed:
+		if item.Fixed == nil || len(item.Fixed.Data) != int(field.Fixed.Size) {
+			return enc, ErrItemInvalid
+		}
+		enc.Fixed = &Fixed{Data: item.Fixed.Data}
+
+	case uap.Extended:
+		if item.Extended == nil {
+			return enc, ErrItemInvalid
+		}
+		tmp, err := encodeExtended(*item.Extended, field.Extended.PrimarySize, field.Extended.SecondarySize)
+		if err != nil {
+			return enc, err
+		}
+		enc.Extended = &tmp
+
+	case uap.Explicit:
+		if item.Explicit == nil || len(item.Explicit.Data)+1 > 0xff {
+			return enc, ErrItemInvalid
+		}
+		enc.Explicit = &Explicit{
+			Len:  uint8(len(item.Explicit.Data) + 1),
+			Data: item.Explicit.Data,
+		}
+
+	case uap.Repetitive:
+		size := int(field.Repetitive.SubItemSize)
+		if item.Repetitive == nil || size == 0 || len(item.Repetitive.Data)%size != 0 ||
+			len(item.Repetitive.Data)/size > 0xff {
+			return enc, ErrItemInvalid
+		}
+		enc.Repetitive = &Repetitive{
+			Rep:  uint8(len(item.Repetitive.Data) / size),
+			Data: item.Repetitive.Data,
+		}
+
+	case uap.Compound:
+		if item.Compound == nil {
+			return enc, ErrItemInvalid
+		}
+		tmp, err := encodeCompound(*item.Compound, field.Compound)
+		if err != nil {
+			return enc, err
+		}
+		enc.Compound = &tmp
+
+	case uap.SP, uap.RE:
+		if item.SP == nil {
+			return enc, ErrItemInvalid
+		}
+		tmp := SpecialPurpose{Data: item.SP.Data}
+		if item.SP.Compound != nil && len(field.Compound) != 0 {
+			cp, err := encodeCompound(*item.SP.Compound, field.Compound)
+			if err != nil {
+				return enc, err
+			}
+			tmp.Compound = &cp
+			tmp.Data = cp.Payload()
+		}
+		if len(tmp.Data)+1 > 0xff {
+			return enc, ErrItemInvalid
+		}
+		tmp.Len = uint8(len(tmp.Data) + 1)
+		enc.SP = &tmp
+
+	case uap.RFS:
+		if item.RFS == nil {
+			return enc, ErrItemInvalid
+		}
+		tmp, err := encodeRFS(*item.RFS, fields)
+		if err != nil {
+			return enc, err
+		}
+		enc.RFS = &tmp
+
+	default:
+		return enc, ErrDataFieldUnknown
+	}
+	return enc, nil
+}
+
+// encodeRFS returns a copy of a RFS item with N computed, the FRNs are resolved with the items of the record.
+func encodeRFS(rfs RandomFieldSequencing, fields []uap.DataField) (RandomFieldSequencing, error) {
+	enc := RandomFieldSequencing{}
+	if len(rfs.Sequence) == 0 || len(rfs.Sequence) > 0xff {
+		return enc, ErrItemInvalid
+	}
+	enc.N = uint8(len(rfs.Sequence))
+	for _, rf := range rfs.Sequence {
+		field, found := rfsField(rf.FRN, fields)
+		if !found {
+			return enc, ErrFRNUnknown
+		}
+		if field.Type == uap.RFS {
+			return enc, ErrDataFieldUnknown
+		}
+		tmp, err := encodeItem(rf.Field, field, nil)
+		if err != nil {
+			return enc, err
+		}
+		enc.Sequence = append(enc.Sequence, RandomField{FRN: rf.FRN, Field: tmp})
+	}
+	return enc, nil
+}
+
+// encodeExtended returns a copy of an Extended item with the Field Extension Indicator (FX) of each part set.
+func encodeExtended(ext Extended, primarySize uint8, secondarySize uint8) (Extended, error) {
+	enc := Extended{}
+	if primarySize == 0 || len(ext.Primary) != int(primarySize) {
+		return enc, ErrItemInvalid
+	}
+	if len(ext.Secondary) != 0 && (secondarySize == 0 || len(ext.Secondary)%int(secondarySize) != 0) {
+		return enc, ErrItemInvalid
+	}
+
+	enc.Primary = append(enc.Primary, ext.Primary...)
+	if len(ext.Secondary) == 0 {
+		enc.Primary[primarySize-1] &= 0xfe
+		return enc, nil
+	}
+	enc.Primary[primarySize-1] |= 0x01
+
+	enc.Secondary = append(enc.Secondary, ext.Secondary...)
+	for i := int(secondarySize) - 1; i < len(enc.Secondary); i += int(secondarySize) {
+		if i == len(enc.Secondary)-1 {
+			enc.Secondary[i] &= 0xfe
+		} else {
+			enc.Secondary[i] |= 0x01
+		}
+	}
+	return enc, nil
+}
+
+// encodeCompound returns a copy of a Compound item with its primary subfield computed from the data subfields.
+func encodeCompound(cp Compound, fields []uap.DataField) (Compound, error) {
+	enc := Compound{}
+	subItems := make([]Item, len(cp.Secondary))
+	copy(subItems, cp.Secondary)
+	sort.SliceStable(subItems, func(i, j int) bool { return subItems[i].Meta.FRN < subItems[j].Meta.FRN })
+
+	var frnIndex []uint8
+	for i, sub := range subItems {
+		frn := sub.Meta.FRN
+		if frn == 0 || int(frn) > len(fields) || (i > 0 && subItems[i-1].Meta.FRN == frn) {
+			return enc, ErrFRNUnknown
+		}
+		field := fields[frn-1]
+		switch field.Type {
+		case uap.Fixed, uap.Extended, uap.Explicit, uap.Repetitive:
+		default:
+			return enc, ErrDataFieldUnknown
+		}
+		tmp, err := encodeItem(sub, field, nil)
+		if err != nil {
+			return enc, err
+		}
+		enc.Secondary = append(enc.Secondary, tmp)
+		frnIndex = append(frnIndex, frn)
+	}
+	if len(frnIndex) == 0 {
+		return enc, ErrRecordEmpty
+	}
+	enc.Primary = FspecFromIndex(frnIndex)
+	return enc, nil
+}
+
+// FspecFromIndex returns the FSPEC corresponding to an array of FRNs (Field Reference Number of Items).
+// In other words, it is the inverse of FspecIndex, the FX bit is set on every octet except the last one.
+// e.g. frnIndex = []uint8{1, 3, 5, 8} => fspec = 1010 1011 1000 0000
+func FspecFromIndex(frnIndex []uint8) []byte {
+	var fspec []byte
+	for _, frn := range frnIndex {
+		fspec = appendFRN(fspec, frn)
+	}
+	return fspec
+}
+
+// appendFRN sets the bit of frn in fspec, see FspecFromIndex. The missing octets are appended and the FX bit
+// of the octets preceding them is set. The FRN 0 is ignored.
+func appendFRN(fspec []byte, frn uint8) []byte {
+	if frn == 0 {
+		return fspec
+	}
+	j := int(frn-1) / 7
+	for len(fspec) <= j {
+		if len(fspec) > 0 {
+			fspec[len(fspec)-1] |= 0x01
+		}
+		fspec = append(fspec, 0)
+	}
+	fspec[j] |= 0x80 >> (uint(frn-1) % 7)
+	return fspec
+}
+
+// Encode computes the LEN field of the DataBlock from its Records and returns the data block in byte:
+// CAT + LEN + N * RECORD(S). The records of a raw data block (see WithRawUnknown) are encoded unchanged.
+func (db *DataBlock) Encode() ([]byte, error) {
+	length := 3
+	var records [][]byte
+	if db.Raw != nil {
+		records = append(records, db.Raw)
+		length += len(db.Raw)
+	}
+	for _, rec := range db.Records {
+		if rec.Cat != db.Category {
+			return nil, ErrCategoryMismatch
+		}
+		if len(rec.Items) == 0 {
+			return nil, ErrRecordEmpty
+		}
+		rec.block = db
+		tmp := rec.Payload()
+		length += len(tmp)
+		records = append(records, tmp)
+	}
+	if len(records) == 0 {
+		return nil, ErrRecordEmpty
+	}
+	if length > 0xffff {
+		return nil, ErrOversized
+	}
+	db.Len = uint16(length)
+
+	pd := make([]byte, 0, length)
+	pd = append(pd, db.Category, byte(db.Len>>8), byte(db.Len&0xff))
+	for _, tmp := range records {
+		pd = append(pd, tmp...)
+	}
+	return pd, nil
+}
+
+// Encode returns the concatenation of its encoded DataBlocks in byte.
+func (w *WrapperDataBlock) Encode() ([]byte, error) {
+	var pd []byte
+	for _, db := range w.DataBlocks {
+		tmp, err := db.Encode()
+		if err != nil {
+			return nil, err
+		}
+		pd = append(pd, tmp...)
+	}
+	return pd, nil
+}
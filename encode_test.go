@@ -0,0 +1,364 @@
+package goasterix
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/mokhtarimokhtar/goasterix/uap"
+	"github.com/mokhtarimokhtar/goasterix/util"
+)
+
+func TestFspecFromIndex(t *testing.T) {
+	type fspecTest struct {
+		input  []uint8
+		output []byte
+	}
+	// Arrange
+	dataSet := []fspecTest{
+		{input: []uint8{1}, output: []byte{0x80}},
+		{input: []uint8{7}, output: []byte{0x02}},
+		{input: []uint8{8}, output: []byte{0x01, 0x80}},
+		{input: []uint8{1, 2, 3, 4, 5, 6, 7}, output: []byte{0xfe}},
+		{input: []uint8{1, 3, 5, 7}, output: []byte{0xaa}},
+		{input: []uint8{1, 2, 3, 5, 6, 7, 8, 11, 12}, output: []byte{0xef, 0x98}},
+		{input: []uint8{22}, output: []byte{0x01, 0x01, 0x01, 0x80}},
+		{input: nil, output: nil},
+	}
+
+	for _, row := range dataSet {
+		// Act
+		fspec := FspecFromIndex(row.input)
+
+		// Assert
+		if bytes.Equal(fspec, row.output) == false {
+			t.Errorf("FAIL: % X; Expected: % X", fspec, row.output)
+		} else {
+			t.Logf("SUCCESS: % X; Expected: % X", fspec, row.output)
+		}
+		if bytes.Equal(FspecIndex(fspec), row.input) == false {
+			t.Errorf("FAIL: % X; Expected: % X", FspecIndex(fspec), row.input)
+		} else {
+			t.Logf("SUCCESS: % X; Expected: % X", FspecIndex(fspec), row.input)
+		}
+	}
+}
+
+func TestRecordEncode_Cat4Test(t *testing.T) {
+	// Arrange
+	items := map[uint8]Item{
+		1: {Fixed: &Fixed{Data: []byte{0xff, 0xff}}},
+		2: {Extended: &Extended{Primary: []byte{0xfe}, Secondary: []byte{0xff, 0xff}}},
+		3: {Explicit: &Explicit{Data: []byte{0xff, 0xff}}},
+		4: {Repetitive: &Repetitive{Data: []byte{0xff, 0xff, 0xff, 0xff}}},
+		5: {Compound: &Compound{
+			Secondary: []Item{
+				{Meta: MetaItem{FRN: 8}, Fixed: &Fixed{Data: []byte{0xff, 0xff}}},
+				{Meta: MetaItem{FRN: 1}, Fixed: &Fixed{Data: []byte{0xff}}},
+				{Meta: MetaItem{FRN: 3}, Extended: &Extended{Primary: []byte{0xff}, Secondary: []byte{0xff}}},
+				{Meta: MetaItem{FRN: 5}, Repetitive: &Repetitive{Data: []byte{0xff, 0xff, 0xff, 0xff}}},
+				{Meta: MetaItem{FRN: 7}, Explicit: &Explicit{Data: []byte{0xff, 0xff, 0xff}}},
+			},
+		}},
+	}
+	output, _ := util.HexStringToByte("f8 ffff fffffe 03ffff 02ffffffff ab80 ff fffe 02ffffffff 04ffffff ffff")
+	rec := NewRecord()
+
+	// Act
+	data, err := rec.Encode(items, uap.Cat4Test)
+
+	// Assert
+	if err != nil {
+		t.Errorf("FAIL: error = %v; Expected: %v", err, nil)
+	} else {
+		t.Logf("SUCCESS: error: %v; Expected: %v", err, nil)
+	}
+	if bytes.Equal(data, output) == false {
+		t.Errorf("FAIL: data = % X; Expected: % X", data, output)
+	} else {
+		t.Logf("SUCCESS: data = % X; Expected: % X", data, output)
+	}
+
+	decoded := NewRecord()
+	_, _ = decoded.Decode(data, uap.Cat4Test)
+	if reflect.DeepEqual(decoded.Items, rec.Items) == false {
+		t.Errorf("FAIL: %v; \nExpected: %v", rec.Items, decoded.Items)
+	} else {
+		t.Logf("SUCCESS: %v; Expected: %v", rec.Items, decoded.Items)
+	}
+}
+
+func TestRecordEncode_Conditional(t *testing.T) {
+	// Arrange
+	items := map[uint8]Item{
+		10: {Fixed: &Fixed{Data: []byte{0x80}}},
+		11: {Fixed: &Fixed{Data: []byte{0xff}}},
+		12: {Fixed: &Fixed{Data: []byte{0xff, 0xff}}},
+	}
+	output, _ := util.HexStringToByte("01 38 80ff ffff")
+	rec := NewRecord()
+
+	// Act
+	data, err := rec.Encode(items, uap.Cat4Test)
+
+	// Assert
+	if err != nil {
+		t.Errorf("FAIL: error = %v; Expected: %v", err, nil)
+	} else {
+		t.Logf("SUCCESS: error: %v; Expected: %v", err, nil)
+	}
+	if bytes.Equal(data, output) == false {
+		t.Errorf("FAIL: data = % X; Expected: % X", data, output)
+	} else {
+		t.Logf("SUCCESS: data = % X; Expected: % X", data, output)
+	}
+}
+
+func TestRecordEncode_RFS(t *testing.T) {
+	// Arrange
+	items := map[uint8]Item{
+		6: {RFS: &RandomFieldSequencing{
+			Sequence: []RandomField{
+				{FRN: 3, Field: Item{Explicit: &Explicit{Data: []byte{0xff, 0xff}}}},
+				{FRN: 1, Field: Item{Fixed: &Fixed{Data: []byte{0xff, 0xff}}}},
+			},
+		}},
+	}
+	output, _ := util.HexStringToByte("04 02 03 03ffff 01 ffff")
+	rec := NewRecord()
+
+	// Act
+	data, err := rec.Encode(items, uap.Cat4Test)
+
+	// Assert
+	if err != nil {
+		t.Errorf("FAIL: error = %v; Expected: %v", err, nil)
+	} else {
+		t.Logf("SUCCESS: error: %v; Expected: %v", err, nil)
+	}
+	if bytes.Equal(data, output) == false {
+		t.Errorf("FAIL: data = % X; Expected: % X", data, output)
+	} else {
+		t.Logf("SUCCESS: data = % X; Expected: % X", data, output)
+	}
+
+	decoded := NewRecord()
+	_, _ = decoded.Decode(data, uap.Cat4Test)
+	if bytes.Equal(decoded.Payload(), data) == false {
+		t.Errorf("FAIL: payload = % X; Expected: % X", decoded.Payload(), data)
+	} else {
+		t.Logf("SUCCESS: payload = % X; Expected: % X", decoded.Payload(), data)
+	}
+}
+
+func TestRecordEncode_Expansion(t *testing.T) {
+	// Arrange
+	// Cat4Test with the sub-profile of RE (FRN 8), SP (FRN 9) without sub-profile
+	stdUAP := uap.Cat4Test
+	stdUAP.ReservedExpansion = []uap.DataField{
+		{FRN: 1, DataItem: "Sub/001", Type: uap.Fixed, Fixed: uap.FixedField{Size: 1}},
+		{FRN: 2, DataItem: "Sub/002", Type: uap.Explicit},
+	}
+	items := map[uint8]Item{
+		8: {SP: &SpecialPurpose{Compound: &Compound{
+			Secondary: []Item{
+				{Meta: MetaItem{FRN: 2}, Explicit: &Explicit{Data: []byte{0xbb, 0xcc}}},
+				{Meta: MetaItem{FRN: 1}, Fixed: &Fixed{Data: []byte{0xaa}}},
+			},
+		}}},
+		9: {SP: &SpecialPurpose{Data: []byte{0xff, 0xfe}}},
+	}
+	output, _ := util.HexStringToByte("01c0 06 c0 aa 03bbcc 03 fffe")
+	rec := NewRecord()
+
+	// Act
+	data, err := rec.Encode(items, stdUAP)
+
+	// Assert
+	if err != nil {
+		t.Errorf("FAIL: error = %v; Expected: %v", err, nil)
+	} else {
+		t.Logf("SUCCESS: error: %v; Expected: %v", err, nil)
+	}
+	if bytes.Equal(data, output) == false {
+		t.Errorf("FAIL: data = % X; Expected: % X", data, output)
+	} else {
+		t.Logf("SUCCESS: data = % X; Expected: % X", data, output)
+	}
+
+	decoded := NewRecord()
+	_, _ = decoded.Decode(data, stdUAP)
+	if bytes.Equal(decoded.Payload(), data) == false {
+		t.Errorf("FAIL: payload = % X; Expected: % X", decoded.Payload(), data)
+	} else {
+		t.Logf("SUCCESS: payload = % X; Expected: % X", decoded.Payload(), data)
+	}
+}
+
+func TestRecordEncode_Error(t *testing.T) {
+	// Setup
+	type dataTest struct {
+		TestCase string
+		items    map[uint8]Item
+		err      error
+	}
+	dataSet := []dataTest{
+		{
+			TestCase: "empty record",
+			items:    map[uint8]Item{},
+			err:      ErrRecordEmpty,
+		},
+		{
+			TestCase: "FRN out of UAP",
+			items:    map[uint8]Item{30: {Fixed: &Fixed{Data: []byte{0xff}}}},
+			err:      ErrFRNUnknown,
+		},
+		{
+			TestCase: "Fixed wrong size",
+			items:    map[uint8]Item{1: {Fixed: &Fixed{Data: []byte{0xff}}}},
+			err:      ErrItemInvalid,
+		},
+		{
+			TestCase: "Fixed missing",
+			items:    map[uint8]Item{1: {Extended: &Extended{Primary: []byte{0xff}}}},
+			err:      ErrItemInvalid,
+		},
+		{
+			TestCase: "Extended wrong secondary size",
+			items:    map[uint8]Item{2: {Extended: &Extended{Primary: []byte{0xff}, Secondary: []byte{0xff}}}},
+			err:      ErrItemInvalid,
+		},
+		{
+			TestCase: "Repetitive wrong size",
+			items:    map[uint8]Item{4: {Repetitive: &Repetitive{Data: []byte{0xff, 0xff, 0xff}}}},
+			err:      ErrItemInvalid,
+		},
+		{
+			TestCase: "Compound unknown subfield",
+			items: map[uint8]Item{5: {Compound: &Compound{
+				Secondary: []Item{{Meta: MetaItem{FRN: 9}, Fixed: &Fixed{Data: []byte{0xff}}}},
+			}}},
+			err: ErrFRNUnknown,
+		},
+		{
+			TestCase: "Spare",
+			items:    map[uint8]Item{7: {Fixed: &Fixed{Data: []byte{0xff}}}},
+			err:      ErrDataFieldUnknown,
+		},
+	}
+
+	for _, row := range dataSet {
+		// Arrange
+		rec := NewRecord()
+
+		// Act
+		data, err := rec.Encode(row.items, uap.Cat4Test)
+
+		// Assert
+		if err != row.err {
+			t.Errorf("FAIL: %s - error = %v; Expected: %v", row.TestCase, err, row.err)
+		} else {
+			t.Logf("SUCCESS: error: %v; Expected: %v", err, row.err)
+		}
+		if data != nil {
+			t.Errorf("FAIL: %s - data = % X; Expected: %v", row.TestCase, data, nil)
+		} else {
+			t.Logf("SUCCESS: data = % X; Expected: %v", data, nil)
+		}
+	}
+}
+
+func TestDataBlockEncode(t *testing.T) {
+	// Arrange
+	input := "220014f6083602429b7110940028200094008000"
+	output, _ := util.HexStringToByte(input)
+	dataB := NewDataBlock()
+	_, _ = dataB.Decode(output)
+	dataB.Len = 0
+
+	// Act
+	data, err := dataB.Encode()
+
+	// Assert
+	if err != nil {
+		t.Errorf("FAIL: error = %v; Expected: %v", err, nil)
+	} else {
+		t.Logf("SUCCESS: error: %v; Expected: %v", err, nil)
+	}
+	if bytes.Equal(data, output) == false {
+		t.Errorf("FAIL: data = % X; Expected: % X", data, output)
+	} else {
+		t.Logf("SUCCESS: data = % X; Expected: % X", data, output)
+	}
+	if dataB.Len != 20 {
+		t.Errorf("FAIL: Len = %v; Expected: %v", dataB.Len, 20)
+	} else {
+		t.Logf("SUCCESS: Len = %v; Expected: %v", dataB.Len, 20)
+	}
+}
+
+func TestDataBlockEncode_Error(t *testing.T) {
+	// Setup
+	type dataTest struct {
+		TestCase string
+		dataB    *DataBlock
+		err      error
+	}
+	dataSet := []dataTest{
+		{
+			TestCase: "without record",
+			dataB:    &DataBlock{Category: 48},
+			err:      ErrRecordEmpty,
+		},
+		{
+			TestCase: "category mismatch",
+			dataB: &DataBlock{
+				Category: 48,
+				Records:  []*Record{{Cat: 34, Fspec: []byte{0x80}, Items: []Item{{}}}},
+			},
+			err: ErrCategoryMismatch,
+		},
+	}
+
+	for _, row := range dataSet {
+		// Act
+		_, err := row.dataB.Encode()
+
+		// Assert
+		if err != row.err {
+			t.Errorf("FAIL: %s - error = %v; Expected: %v", row.TestCase, err, row.err)
+		} else {
+			t.Logf("SUCCESS: error: %v; Expected: %v", err, row.err)
+		}
+	}
+}
+
+func TestWrapperDataBlockEncode(t *testing.T) {
+	// Arrange
+	items := map[uint8]Item{
+		1: {Fixed: &Fixed{Data: []byte{0x08, 0x36}}},
+		2: {Fixed: &Fixed{Data: []byte{0x02}}},
+		3: {Fixed: &Fixed{Data: []byte{0x42, 0x9b, 0x71}}},
+	}
+	output, _ := util.HexStringToByte("22 000a e0 0836 02 429b71 22 000a e0 0836 02 429b71")
+	rec := NewRecord()
+	_, _ = rec.Encode(items, uap.Cat034V127)
+	dataB := &DataBlock{Category: 34, Records: []*Record{rec}}
+	w, _ := NewWrapperDataBlock()
+	w.DataBlocks = []*DataBlock{dataB, dataB}
+
+	// Act
+	data, err := w.Encode()
+
+	// Assert
+	if err != nil {
+		t.Errorf("FAIL: error = %v; Expected: %v", err, nil)
+	} else {
+		t.Logf("SUCCESS: error: %v; Expected: %v", err, nil)
+	}
+	if bytes.Equal(data, output) == false {
+		t.Errorf("FAIL: data = % X; Expected: % X", data, output)
+	} else {
+		t.Logf("SUCCESS: data = % X; Expected: % X", data, output)
+	}
+}
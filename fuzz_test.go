@@ -0,0 +1,117 @@
+package goasterix
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mokhtarimokhtar/goasterix/uap"
+	"github.com/mokhtarimokhtar/goasterix/util"
+)
+
+// fuzzSeeds are valid data blocks of several categories used as seed corpus.
+var fuzzSeeds = []string{
+	"220014f6083602429b7110940028200094008000",
+	"30002dffff02 0836 429b52 a0 94c70181 0913 02d0 6002b7 490d01 38a178cf4220 02e79a5d27a00c0060a3280030a4000040 063a 00800080 0743ce5b 40 20f5",
+	"1e009fbffb0160088358052c7dfc04010e0fe86601c4720e008c008c01beff8bf027190439cc821885050e08203fff01605800847dfc04010e0a6968a7d6160e029d02a2fc660498f8feb917010c4caa2358f171dc15603ffb01605801d27dfc04010e0b1a6d60cf860e02d002d0fd460370f017010c4d02a6286076d518203ffb805805387dfc040f0e0e007593ccb20e00500050feb9ff5df017010c2205",
+	"1a000e ff80 ffff 01 03ffff 01ffff",
+	"1a0006 0180 00",
+	"1a0003",
+}
+
+func fuzzAddSeeds(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		data, err := util.HexStringToByte(seed)
+		if err != nil {
+			f.Fatalf("seed %s: %v", seed, err)
+		}
+		f.Add(data)
+	}
+}
+
+func FuzzDataBlockDecode(f *testing.F) {
+	fuzzAddSeeds(f)
+	d := NewDecoder(WithStrict())
+	f.Fuzz(func(t *testing.T, data []byte) {
+		db, unRead, err := d.DecodeDataBlock(data)
+		if unRead < 0 || unRead > len(data) {
+			t.Errorf("unRead = %d out of data (%d bytes), error: %v", unRead, len(data), err)
+		}
+		if err != nil {
+			return
+		}
+
+		if payload := bytes.Join(db.Payload(), nil); !bytes.Equal(payload, data[:int(db.Len)]) {
+			t.Errorf("payload = %x; Expected: %x", payload, data[:int(db.Len)])
+		}
+
+		// the data block encoded is decoded again with the same records, see TestRoundTrip_DataBlocks
+		encoded, err := db.Encode()
+		if failure := checkDataBlock(db, encoded, err, data[:int(db.Len)]); failure != "" {
+			t.Error(failure)
+		}
+	})
+}
+
+func FuzzWrapperDataBlockDecode(f *testing.F) {
+	fuzzAddSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		w, _ := NewWrapperDataBlock()
+		_, _ = w.Decode(data)
+
+		w, _ = NewWrapperDataBlock()
+		_ = w.DecodeTolerant(data)
+	})
+}
+
+func FuzzRecordDecode(f *testing.F) {
+	profiles := []uap.StandardUAP{uap.Cat4Test, uap.Cat001V12, uap.Cat048V127, uap.Cat062V119, uap.Cat030ArtasV62}
+	fuzzAddSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if len(data) < 1 {
+			return
+		}
+		// the first byte selects the profile
+		stdUAP := profiles[int(data[0])%len(profiles)]
+		rec := NewRecord()
+		unRead, err := rec.Decode(data[1:], stdUAP)
+		if unRead < 0 || unRead > len(data)-1 {
+			t.Errorf("unRead = %d out of data (%d bytes), error: %v", unRead, len(data)-1, err)
+		}
+		if err == nil {
+			_ = rec.String()
+			if payload := rec.Payload(); !bytes.Equal(payload, data[1:len(data)-unRead]) {
+				t.Errorf("payload = %x; Expected: %x", payload, data[1:len(data)-unRead])
+			}
+		}
+
+		// the record encoded is decoded again with the same items, see TestRoundTrip_Records
+		if err == nil && len(rec.Items) != 0 {
+			decoded := data[1 : len(data)-unRead]
+			encoded, errEncode := encodeRecord(rec, stdUAP)
+			isCanonical := canonicalItems(rec.Fspec, rec.Items)
+			switch {
+			case errEncode != nil && isCanonical:
+				t.Errorf("%x: Encode error: %v", decoded, errEncode)
+			case errEncode == nil && isCanonical && !bytes.Equal(encoded, decoded):
+				t.Errorf("record encoded = %x; Expected: %x", encoded, decoded)
+			case errEncode == nil:
+				again := NewRecord()
+				if unRead, err := again.Decode(encoded, stdUAP); err != nil || unRead != 0 || !sameItems(again.Items, rec.Items) {
+					t.Errorf("%x decoded again = %v, error: %v; Expected: %v", encoded, again.String(), err, rec.String())
+				}
+			}
+		}
+
+		// the lazy decoding locates the same items
+		lazy := NewLazyRecord()
+		lazyUnRead, lazyErr := lazy.Decode(data[1:], stdUAP)
+		if lazyUnRead != unRead || (lazyErr == nil) != (err == nil) {
+			t.Errorf("lazy unRead = %d, error: %v; Expected: %d, %v", lazyUnRead, lazyErr, unRead, err)
+		}
+		if lazyErr == nil {
+			if full, err := lazy.Record(); err != nil || len(full.Items) != len(rec.Items) {
+				t.Errorf("lazy Record() error: %v", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,289 @@
+// Package generic decodes the items of asterix records into named values with the bit-level definitions
+// (uap.SubField) of the User Application Profile, without code specific to a category.
+package generic
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mokhtarimokhtar/goasterix"
+	"github.com/mokhtarimokhtar/goasterix/uap"
+)
+
+var (
+	// ErrFieldMismatch reports that an item does not match the type of its data field.
+	ErrFieldMismatch = errors.New("[ASTERIX] item does not match its data field")
+
+	// ErrSubFieldInvalid reports that the bits of a subfield are out of the data of its item.
+	ErrSubFieldInvalid = errors.New("[ASTERIX] subfield bits out of range")
+)
+
+// Value is a decoded subfield.
+// Raw is the unsigned value of the bits, Value is:
+// a string for an enumeration or a non-numeric format, a float64 for a scaled value,
+// an int64 for a signed value and an uint64 otherwise.
+type Value struct {
+	Name  string
+	Raw   uint64
+	Value interface{}
+	Unit  string
+}
+
+// Field contains the decoded values of an item.
+// Values are the subfields of a Fixed, Extended or Explicit item, or of a RE or SP item without sub-profile.
+// Items are the repetitions of a Repetitive item or the data subfields of a Compound, RE or SP item.
+type Field struct {
+	DataItem string
+	Values   []Value
+	Items    []Field
+}
+
+// Get returns the value of the subfield name.
+func (f Field) Get(name string) (Value, bool) {
+	for _, v := range f.Values {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return Value{}, false
+}
+
+// Decode returns the values of an item with the subfields of its data field.
+// An item without subfields definition returns a Field without values.
+func Decode(item goasterix.Item, field uap.DataField) (Field, error) {
+	f := Field{DataItem: field.DataItem}
+	var err error
+
+	switch field.Type {
+	case uap.Fixed:
+		if item.Fixed == nil {
+			return f, ErrFieldMismatch
+		}
+		f.Values, err = values(item.Fixed.Data, field.SubFields)
+
+	case uap.Extended:
+		if item.Extended == nil {
+			return f, ErrFieldMismatch
+		}
+		f.Values, err = extendedValues(*item.Extended, field)
+
+	case uap.Explicit:
+		if item.Explicit == nil {
+			return f, ErrFieldMismatch
+		}
+		f.Values, err = values(item.Explicit.Data, field.SubFields)
+
+	case uap.Repetitive:
+		if item.Repetitive == nil {
+			return f, ErrFieldMismatch
+		}
+		size := int(field.Repetitive.SubItemSize)
+		for i := 0; size != 0 && i+size <= len(item.Repetitive.Data); i += size {
+			rep := Field{DataItem: field.DataItem}
+			rep.Values, err = values(item.Repetitive.Data[i:i+size], field.SubFields)
+			if err != nil {
+				return f, err
+			}
+			f.Items = append(f.Items, rep)
+		}
+
+	case uap.Compound:
+		if item.Compound == nil {
+			return f, ErrFieldMismatch
+		}
+		f.Items, err = compoundItems(*item.Compound, field.Compound)
+
+	case uap.SP, uap.RE:
+		if item.SP == nil {
+			return f, ErrFieldMismatch
+		}
+		if item.SP.Compound != nil {
+			f.Items, err = compoundItems(*item.SP.Compound, field.Compound)
+		} else {
+			f.Values, err = values(item.SP.Data, field.SubFields)
+		}
+
+	default:
+		// RFS and Spare fields have no subfields definition
+	}
+	return f, err
+}
+
+// DecodeRecord returns the values of each item of a record decoded with stdUAP,
+// the data fields of a conditional UAP and the sub-profiles of RE and SP fields are resolved like Record.Decode
+// (see goasterix.Plan.Fields).
+func DecodeRecord(rec *goasterix.Record, stdUAP uap.StandardUAP) ([]Field, error) {
+	fields, err := goasterix.Compile(stdUAP).Fields(rec)
+	if err != nil {
+		return nil, err
+	}
+	var result []Field
+	for _, item := range rec.Items {
+		frn := item.Meta.FRN
+		if frn == 0 || int(frn) > len(fields) {
+			return result, goasterix.ErrFRNUnknown
+		}
+		f, err := Decode(item, fields[frn-1])
+		if err != nil {
+			return result, err
+		}
+		result = append(result, f)
+	}
+	return result, nil
+}
+
+// compoundItems returns the values of the data subfields of a compound item.
+func compoundItems(cp goasterix.Compound, fields []uap.DataField) ([]Field, error) {
+	var items []Field
+	for _, sub := range cp.Secondary {
+		frn := sub.Meta.FRN
+		if frn == 0 || int(frn) > len(fields) {
+			return items, goasterix.ErrFRNUnknown
+		}
+		f, err := Decode(sub, fields[frn-1])
+		if err != nil {
+			return items, err
+		}
+		items = append(items, f)
+	}
+	return items, nil
+}
+
+// extendedValues returns the values of an extended item, the subfields of an absent extension are ignored.
+func extendedValues(ext goasterix.Extended, field uap.DataField) ([]Value, error) {
+	var vs []Value
+	for _, sf := range field.SubFields {
+		var data []byte
+		if sf.Part == 0 {
+			data = ext.Primary
+		} else {
+			size := int(field.Extended.SecondarySize)
+			end := int(sf.Part) * size
+			if size == 0 || end > len(ext.Secondary) {
+				continue
+			}
+			data = ext.Secondary[end-size : end]
+		}
+		v, err := value(data, sf)
+		if err != nil {
+			return vs, err
+		}
+		vs = append(vs, v)
+	}
+	return vs, nil
+}
+
+// values returns the values of the subfields of data, the subfields of the extensions (see extendedValues) are ignored.
+func values(data []byte, subFields []uap.SubField) ([]Value, error) {
+	var vs []Value
+	for _, sf := range subFields {
+		if sf.Part != 0 {
+			continue
+		}
+		v, err := value(data, sf)
+		if err != nil {
+			return vs, err
+		}
+		vs = append(vs, v)
+	}
+	return vs, nil
+}
+
+// value returns the value of one subfield of data.
+func value(data []byte, sf uap.SubField) (Value, error) {
+	v := Value{Name: sf.Name, Unit: sf.Unit}
+	raw, ok := Bits(data, sf.From, sf.To)
+	if !ok {
+		return v, ErrSubFieldInvalid
+	}
+	v.Raw = raw
+	width := int(sf.From-sf.To) + 1
+
+	switch sf.Format {
+	case uap.Octal:
+		v.Value = fmt.Sprintf("%0*o", (width+2)/3, raw)
+		return v, nil
+	case uap.Hex:
+		v.Value = fmt.Sprintf("%0*x", (width+3)/4, raw)
+		return v, nil
+	case uap.ICAO6:
+		v.Value = characters(raw, width, 6)
+		return v, nil
+	case uap.ASCII:
+		v.Value = characters(raw, width, 8)
+		return v, nil
+	}
+
+	if sf.Enum != nil {
+		meaning, found := sf.Enum[raw]
+		if !found {
+			meaning = strconv.FormatUint(raw, 10)
+		}
+		v.Value = meaning
+		return v, nil
+	}
+
+	switch {
+	case sf.Signed && sf.Scale != 0:
+		v.Value = float64(signed(raw, width)) * sf.Scale
+	case sf.Scale != 0:
+		v.Value = float64(raw) * sf.Scale
+	case sf.Signed:
+		v.Value = signed(raw, width)
+	default:
+		v.Value = raw
+	}
+	return v, nil
+}
+
+// Bits returns the unsigned value of the bits from (most significant) to (least significant) of data,
+// bit 1 is the least significant bit of the last octet.
+// It returns false if the bits are out of data or wider than 64 bits.
+func Bits(data []byte, from uint8, to uint8) (uint64, bool) {
+	n := len(data) * 8
+	if to == 0 || from < to || int(from) > n || from-to >= 64 {
+		return 0, false
+	}
+	var v uint64
+	for b := int(from); b >= int(to); b-- {
+		i := n - b // position from the most significant bit of data
+		v = v<<1 | uint64(data[i/8]>>(7-uint(i%8))&0x01)
+	}
+	return v, true
+}
+
+// signed returns the two's complement value of raw on width bits.
+func signed(raw uint64, width int) int64 {
+	if width < 64 && raw&(1<<uint(width-1)) != 0 {
+		return int64(raw) - int64(1)<<uint(width)
+	}
+	return int64(raw)
+}
+
+// characters returns the string of the characters of size bits contained in raw (width bits).
+func characters(raw uint64, width int, size int) string {
+	var sb strings.Builder
+	for shift := width - size; shift >= 0; shift -= size {
+		c := byte(raw >> uint(shift) & (1<<uint(size) - 1))
+		if size == 6 {
+			c = icao6(c)
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+// icao6 returns the ASCII character of a 6-bit character (ICAO Annex 10, Volume IV).
+func icao6(c byte) byte {
+	switch {
+	case c >= 1 && c <= 26:
+		return 'A' + c - 1
+	case c >= 48 && c <= 57:
+		return c
+	case c == 32:
+		return ' '
+	}
+	return '?'
+}
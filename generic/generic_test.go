@@ -0,0 +1,281 @@
+package generic
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mokhtarimokhtar/goasterix"
+	"github.com/mokhtarimokhtar/goasterix/uap"
+	"github.com/mokhtarimokhtar/goasterix/util"
+)
+
+func TestBits(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		input        []byte
+		from         uint8
+		to           uint8
+		output       uint64
+		ok           bool
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "whole octet",
+			input:        []byte{0xa5},
+			from:         8,
+			to:           1,
+			output:       0xa5,
+			ok:           true,
+		},
+		{
+			TestCaseName: "across octets",
+			input:        []byte{0x0f, 0xf0},
+			from:         12,
+			to:           5,
+			output:       0xff,
+			ok:           true,
+		},
+		{
+			TestCaseName: "one bit",
+			input:        []byte{0x80, 0x00},
+			from:         16,
+			to:           16,
+			output:       1,
+			ok:           true,
+		},
+		{
+			TestCaseName: "out of data",
+			input:        []byte{0xff},
+			from:         9,
+			to:           1,
+			output:       0,
+			ok:           false,
+		},
+		{
+			TestCaseName: "bit 0",
+			input:        []byte{0xff},
+			from:         8,
+			to:           0,
+			output:       0,
+			ok:           false,
+		},
+	}
+
+	for _, row := range dataSet {
+		// Act
+		v, ok := Bits(row.input, row.from, row.to)
+
+		// Assert
+		if v != row.output || ok != row.ok {
+			t.Errorf("FAIL: %s - value = %v, %v; Expected: %v, %v", row.TestCaseName, v, ok, row.output, row.ok)
+		} else {
+			t.Logf("SUCCESS: value = %v, %v; Expected: %v, %v", v, ok, row.output, row.ok)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		item         goasterix.Item
+		field        uap.DataField
+		output       Field
+		err          error
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "signed scaled",
+			item:         goasterix.Item{Fixed: &goasterix.Fixed{Data: []byte{0x3f, 0xfc}}},
+			field: uap.DataField{
+				DataItem:  "I048/090",
+				Type:      uap.Fixed,
+				SubFields: []uap.SubField{{Name: "FL", From: 14, To: 1, Signed: true, Scale: 0.25, Unit: "FL"}},
+			},
+			output: Field{
+				DataItem: "I048/090",
+				Values:   []Value{{Name: "FL", Raw: 0x3ffc, Value: float64(-1), Unit: "FL"}},
+			},
+			err: nil,
+		},
+		{
+			TestCaseName: "extended without extension",
+			item:         goasterix.Item{Extended: &goasterix.Extended{Primary: []byte{0x40}}},
+			field: uap.DataField{
+				DataItem: "I048/020",
+				Type:     uap.Extended,
+				Extended: uap.ExtendedField{PrimarySize: 1, SecondarySize: 1},
+				SubFields: []uap.SubField{
+					{Name: "TYP", From: 8, To: 6},
+					{Name: "TST", From: 8, To: 8, Part: 1},
+				},
+			},
+			output: Field{
+				DataItem: "I048/020",
+				Values:   []Value{{Name: "TYP", Raw: 2, Value: uint64(2)}},
+			},
+			err: nil,
+		},
+		{
+			TestCaseName: "repetitive",
+			item:         goasterix.Item{Repetitive: &goasterix.Repetitive{Rep: 2, Data: []byte{0x01, 0x02}}},
+			field: uap.DataField{
+				DataItem:   "I026/004",
+				Type:       uap.Repetitive,
+				Repetitive: uap.RepetitiveField{SubItemSize: 1},
+				SubFields:  []uap.SubField{{Name: "A", From: 8, To: 1, Signed: true}},
+			},
+			output: Field{
+				DataItem: "I026/004",
+				Items: []Field{
+					{DataItem: "I026/004", Values: []Value{{Name: "A", Raw: 1, Value: int64(1)}}},
+					{DataItem: "I026/004", Values: []Value{{Name: "A", Raw: 2, Value: int64(2)}}},
+				},
+			},
+			err: nil,
+		},
+		{
+			TestCaseName: "SP without sub-profile",
+			item:         goasterix.Item{SP: &goasterix.SpecialPurpose{Len: 0x02, Data: []byte{0xa5}}},
+			field: uap.DataField{
+				DataItem:  "I026/009",
+				Type:      uap.SP,
+				SubFields: []uap.SubField{{Name: "A", From: 8, To: 5}, {Name: "B", From: 4, To: 1}},
+			},
+			output: Field{
+				DataItem: "I026/009",
+				Values:   []Value{{Name: "A", Raw: 0xa, Value: uint64(0xa)}, {Name: "B", Raw: 0x5, Value: uint64(0x5)}},
+			},
+			err: nil,
+		},
+		{
+			TestCaseName: "subfield out of range",
+			item:         goasterix.Item{Fixed: &goasterix.Fixed{Data: []byte{0xff}}},
+			field: uap.DataField{
+				DataItem:  "I026/001",
+				Type:      uap.Fixed,
+				SubFields: []uap.SubField{{Name: "A", From: 16, To: 1}},
+			},
+			output: Field{DataItem: "I026/001"},
+			err:    ErrSubFieldInvalid,
+		},
+		{
+			TestCaseName: "item mismatch",
+			item:         goasterix.Item{},
+			field:        uap.DataField{DataItem: "I026/001", Type: uap.Fixed},
+			output:       Field{DataItem: "I026/001"},
+			err:          ErrFieldMismatch,
+		},
+	}
+
+	for _, row := range dataSet {
+		// Act
+		f, err := Decode(row.item, row.field)
+
+		// Assert
+		if err != row.err {
+			t.Errorf("FAIL: %s - error = %v; Expected: %v", row.TestCaseName, err, row.err)
+		} else {
+			t.Logf("SUCCESS: error = %v; Expected: %v", err, row.err)
+		}
+		if !reflect.DeepEqual(f, row.output) {
+			t.Errorf("FAIL: %s - field = %v; Expected: %v", row.TestCaseName, f, row.output)
+		} else {
+			t.Logf("SUCCESS: field = %v; Expected: %v", f, row.output)
+		}
+	}
+}
+
+func TestDecodeRecord_CAT048(t *testing.T) {
+	// Arrange
+	input := "ffff02 0836 429b52 a0 94c70181 0913 02d0 6002b7 490d01 38a178cf4220 02e79a5d27a00c0060a3280030a4000040 063a 00800080 0743ce5b 40 20f5"
+	data, _ := util.HexStringToByte(input)
+	rec := goasterix.NewRecord()
+	_, _ = rec.Decode(data, uap.Cat048V127)
+	output := map[string]map[string]interface{}{
+		"I048/010": {"SAC": uint64(8), "SIC": uint64(54)},
+		"I048/140": {"TOD": 34102.640625},
+		"I048/020": {"TYP": "single_modes_roll_call", "SIM": uint64(0)},
+		"I048/040": {"RHO": 148.77734375},
+		"I048/070": {"V": "code_validated", "G": "default", "MODE3A": "4423"},
+		"I048/090": {"V": "code_validated", "FL": float64(180)},
+		"I048/220": {"ADDRESS": "490d01"},
+		"I048/240": {"IDENT": "NJE834H "},
+		"I048/161": {"TRN": uint64(1594)},
+	}
+
+	// Act
+	fields, err := DecodeRecord(rec, uap.Cat048V127)
+
+	// Assert
+	if err != nil {
+		t.Errorf("FAIL: error = %v; Expected: %v", err, nil)
+	} else {
+		t.Logf("SUCCESS: error = %v; Expected: %v", err, nil)
+	}
+	nbOfValues := 0
+	for _, f := range fields {
+		for name, expected := range output[f.DataItem] {
+			nbOfValues++
+			v, found := f.Get(name)
+			if !found || v.Value != expected {
+				t.Errorf("FAIL: %s %s = %v; Expected: %v", f.DataItem, name, v.Value, expected)
+			} else {
+				t.Logf("SUCCESS: %s %s = %v; Expected: %v", f.DataItem, name, v.Value, expected)
+			}
+		}
+	}
+	if nbOfValues != 14 {
+		t.Errorf("FAIL: nbOfValues = %v; Expected: %v", nbOfValues, 14)
+	}
+
+	sam := fields[6].Items[1]
+	if v, _ := sam.Get("SAM"); v.Value != int64(-73) {
+		t.Errorf("FAIL: SAM = %v; Expected: %v", v.Value, -73)
+	} else {
+		t.Logf("SUCCESS: SAM = %v; Expected: %v", v.Value, -73)
+	}
+}
+
+func TestDecodeRecord_Conditional(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		input        string
+		dataItems    []string
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "CAT001 plot",
+			input:        "f0 0831 00 0a8abb2e 3802",
+			dataItems:    []string{"I001/010", "I001/020", "I001/040", "I001/070"},
+		},
+		{
+			TestCaseName: "CAT001 track",
+			input:        "f502 0831 98 01bf 0a1ebb43 022538e2 00",
+			dataItems:    []string{"I001/010", "I001/020", "I001/161", "I001/040", "I001/200", "I001/210"},
+		},
+	}
+
+	for _, row := range dataSet {
+		// Arrange
+		data, _ := util.HexStringToByte(row.input)
+		rec := goasterix.NewRecord()
+		_, _ = rec.Decode(data, uap.Cat001V12)
+
+		// Act
+		fields, err := DecodeRecord(rec, uap.Cat001V12)
+
+		// Assert
+		var dataItems []string
+		for _, f := range fields {
+			dataItems = append(dataItems, f.DataItem)
+		}
+		if err != nil || !reflect.DeepEqual(dataItems, row.dataItems) {
+			t.Errorf("FAIL: %s - error = %v, items = %v; Expected: %v, %v", row.TestCaseName, err, dataItems, nil, row.dataItems)
+		} else {
+			t.Logf("SUCCESS: %s - items = %v", row.TestCaseName, dataItems)
+		}
+	}
+}
@@ -1,5 +1,5 @@
 module github.com/mokhtarimokhtar/goasterix
 
-go 1.16
+go 1.18
 
 require github.com/davecgh/go-spew v1.1.1 // indirect
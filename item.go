@@ -47,6 +47,10 @@ func (i *Item) Payload() []byte {
 		p = i.Repetitive.Payload()
 	case uap.Compound:
 		p = i.Compound.Payload()
+	case uap.RFS:
+		p = i.RFS.Payload()
+	case uap.SP, uap.RE:
+		p = i.SP.Payload()
 	}
 	return p
 }
@@ -65,6 +69,10 @@ func (i *Item) String() string {
 		str = str + ": " + i.Repetitive.String()
 	case uap.Compound:
 		str = str + ": " + i.Compound.String()
+	case uap.RFS:
+		str = str + ": " + i.RFS.String()
+	case uap.SP, uap.RE:
+		str = str + ": " + i.SP.String()
 	}
 	return str
 }
@@ -162,12 +170,51 @@ type RandomFieldSequencing struct {
 	Sequence []RandomField
 }
 
+func (rfs *RandomFieldSequencing) Payload() []byte {
+	var p []byte
+	p = append(p, rfs.N)
+	for _, rf := range rfs.Sequence {
+		p = append(p, rf.FRN)
+		p = append(p, rf.Field.Payload()...)
+	}
+	return p
+}
+
+func (rfs RandomFieldSequencing) String() string {
+	var str string
+	str = "[n: " + hex.EncodeToString([]byte{rfs.N}) + "]"
+	for _, rf := range rfs.Sequence {
+		str = str + "[frn: " + hex.EncodeToString([]byte{rf.FRN}) + "][" + rf.Field.String() + "]"
+	}
+	return str
+}
+
 type RandomField struct {
 	FRN   uint8
 	Field Item
 }
 
+// SpecialPurpose contains a RE (Reserved Expansion) or SP (Special Purpose) field.
+// Data is the content of the field, Compound contains its subfields when the UAP defines a sub-profile.
 type SpecialPurpose struct {
-	Len  uint8
-	Data []byte
+	Len      uint8
+	Data     []byte
+	Compound *Compound
+}
+
+// Payload returns the length indicator followed by the data of the field, the subfields of Compound are not
+// encoded again: they are contained in Data.
+func (sp *SpecialPurpose) Payload() []byte {
+	var p []byte
+	p = append(p, sp.Len)
+	p = append(p, sp.Data...)
+	return p
+}
+
+func (sp *SpecialPurpose) String() string {
+	tmp := []byte{sp.Len}
+	if sp.Compound != nil {
+		return "[len: " + hex.EncodeToString(tmp) + "]" + sp.Compound.String()
+	}
+	return hex.EncodeToString(tmp) + hex.EncodeToString(sp.Data)
 }
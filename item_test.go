@@ -418,3 +418,162 @@ func TestItem_String(t *testing.T) {
 		}
 	}
 }
+
+func TestRandomFieldSequencing_Payload(t *testing.T) {
+	// Arrange
+	rfs := new(RandomFieldSequencing)
+	rfs.N = 0x02
+	rfs.Sequence = []RandomField{
+		{
+			FRN: 0x03,
+			Field: Item{
+				Meta:  MetaItem{Type: uap.Fixed},
+				Fixed: &Fixed{Data: []byte{0xff, 0xff}},
+			},
+		},
+		{
+			FRN: 0x04,
+			Field: Item{
+				Meta:     MetaItem{Type: uap.Explicit},
+				Explicit: &Explicit{Len: 0x02, Data: []byte{0xfe}},
+			},
+		},
+	}
+	output := []byte{0x02, 0x03, 0xff, 0xff, 0x04, 0x02, 0xfe}
+
+	// Act
+	b := rfs.Payload()
+
+	// Assert
+	if bytes.Equal(b, output) == false {
+		t.Errorf("FAIL: sp = % X; Expected: % X", b, output)
+	} else {
+		t.Logf("SUCCESS: sp = % X; Expected: % X", b, output)
+	}
+}
+
+func TestRandomFieldSequencing_String(t *testing.T) {
+	// Arrange
+	item := Item{
+		Meta: MetaItem{DataItem: "I026/006", Type: uap.RFS},
+		RFS: &RandomFieldSequencing{
+			N: 0x01,
+			Sequence: []RandomField{
+				{
+					FRN: 0x01,
+					Field: Item{
+						Meta:  MetaItem{DataItem: "I026/001", Type: uap.Fixed},
+						Fixed: &Fixed{Data: []byte{0xff, 0xff}},
+					},
+				},
+			},
+		},
+	}
+	output := "I026/006: [n: 01][frn: 01][I026/001: ffff]"
+
+	// Act
+	s := item.String()
+
+	// Assert
+	if s != output {
+		t.Errorf("FAIL: s = %s; Expected: %s", s, output)
+	} else {
+		t.Logf("SUCCESS: s = %s; Expected: %s", s, output)
+	}
+}
+
+func TestSpecialPurpose_Payload(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		input        Item
+		output       []byte
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "SP",
+			input: Item{
+				Meta: MetaItem{DataItem: "I026/009", Type: uap.SP},
+				SP:   &SpecialPurpose{Len: 0x03, Data: []byte{0xff, 0xfe}},
+			},
+			output: []byte{0x03, 0xff, 0xfe},
+		},
+		{
+			TestCaseName: "RE with sub-profile",
+			input: Item{
+				Meta: MetaItem{DataItem: "I026/008", Type: uap.RE},
+				SP: &SpecialPurpose{
+					Len:  0x03,
+					Data: []byte{0x80, 0xaa},
+					Compound: &Compound{
+						Primary: []byte{0x80},
+						Secondary: []Item{
+							{Meta: MetaItem{DataItem: "Sub/001", Type: uap.Fixed}, Fixed: &Fixed{Data: []byte{0xaa}}},
+						},
+					},
+				},
+			},
+			output: []byte{0x03, 0x80, 0xaa},
+		},
+	}
+
+	for _, row := range dataSet {
+		// Act
+		b := row.input.Payload()
+
+		// Assert
+		if bytes.Equal(b, row.output) == false {
+			t.Errorf("FAIL: %s - item = % X; Expected: % X", row.TestCaseName, b, row.output)
+		} else {
+			t.Logf("SUCCESS: %s - item = % X; Expected: % X", row.TestCaseName, b, row.output)
+		}
+	}
+}
+
+func TestSpecialPurpose_String(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		input        Item
+		output       string
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "SP",
+			input: Item{
+				Meta: MetaItem{DataItem: "I026/009", Type: uap.SP},
+				SP:   &SpecialPurpose{Len: 0x03, Data: []byte{0xff, 0xfe}},
+			},
+			output: "I026/009: 03fffe",
+		},
+		{
+			TestCaseName: "RE with sub-profile",
+			input: Item{
+				Meta: MetaItem{DataItem: "I026/008", Type: uap.RE},
+				SP: &SpecialPurpose{
+					Len:  0x03,
+					Data: []byte{0x80, 0xaa},
+					Compound: &Compound{
+						Primary: []byte{0x80},
+						Secondary: []Item{
+							{Meta: MetaItem{DataItem: "Sub/001", Type: uap.Fixed}, Fixed: &Fixed{Data: []byte{0xaa}}},
+						},
+					},
+				},
+			},
+			output: "I026/008: [len: 03][primary: 80][Sub/001: aa]",
+		},
+	}
+
+	for _, row := range dataSet {
+		// Act
+		s := row.input.String()
+
+		// Assert
+		if s != row.output {
+			t.Errorf("FAIL: %s - s = %s; Expected: %s", row.TestCaseName, s, row.output)
+		} else {
+			t.Logf("SUCCESS: %s - s = %s; Expected: %s", row.TestCaseName, s, row.output)
+		}
+	}
+}
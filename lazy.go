@@ -0,0 +1,300 @@
+package goasterix
+
+import (
+	"errors"
+	"math/bits"
+
+	"github.com/mokhtarimokhtar/goasterix/uap"
+)
+
+// Span is the position of an item in the data of a LazyRecord.
+// Offset is the position in byte of the data field from the start of the record (its FSPEC), Len is its size.
+// A Span contains no pointer: the spans of a large recording are not scanned by the garbage collector.
+type Span struct {
+	FRN    uint8
+	Offset int
+	Len    int
+}
+
+// LazyRecord is a record whose items are located but not decoded: the decoding reads the FSPEC
+// and computes the span of each data field without building its Item.
+// An Item is decoded only when it is asked for (see Item), e.g. to index or filter a large recording
+// on a few items (I048/010, I048/140) without the cost of the Compound and BDS Repetitive items.
+type LazyRecord struct {
+	Cat   uint8
+	Fspec []byte
+	Spans []Span
+
+	data    []byte      // data of the record: FSPEC + items
+	fields  []planField // items of the record (the variant of a conditional UAP) indexed by FRN
+	storage *Record     // storage of the items decoded, allocated by the first decoding of an item
+	items   []*Item     // items decoded, indexed like Spans
+}
+
+func NewLazyRecord() *LazyRecord {
+	return &LazyRecord{}
+}
+
+// Decode locates the items of a Record (only one record) without decoding them.
+// It returns the number of bytes unread like Record.Decode, the errors of the data fields are detected
+// (see DecodeError): the items of a LazyRecord decoded without error can be decoded.
+// data is copied once, the spans and the items decoded are located in this copy.
+func (rec *LazyRecord) Decode(data []byte, stdUAP uap.StandardUAP) (unRead int, err error) {
+	return rec.DecodePlan(data, profilePlan(stdUAP))
+}
+
+// DecodePlan locates the items of a Record like Decode with the compiled User Application Profile p.
+func (rec *LazyRecord) DecodePlan(data []byte, p *Plan) (unRead int, err error) {
+	tmp := make([]byte, len(data))
+	copy(tmp, data)
+	return rec.decode(tmp, p)
+}
+
+// decode locates the items of the record beginning data, the spans alias data.
+func (rec *LazyRecord) decode(data []byte, p *Plan) (unRead int, err error) {
+	rec.Cat = p.stdUAP.Category
+
+	c := cursor{data: data}
+	rec.Fspec, err = c.fspec()
+	if err != nil {
+		return c.len(), rec.decodeError(err, 0, uap.DataField{})
+	}
+
+	n := 0 // number of items
+	for _, octet := range rec.Fspec {
+		n += bits.OnesCount8(octet & 0xfe)
+	}
+	rec.Spans = make([]Span, 0, n)
+	rec.items = nil
+
+	rec.fields = p.items
+	for frn := nextFRN(rec.Fspec, 0); frn != 0; frn = nextFRN(rec.Fspec, frn) {
+		offset := c.pos
+		if int(frn) > len(rec.fields) {
+			return c.len(), rec.decodeError(ErrFRNUnknown, offset, uap.DataField{FRN: frn})
+		}
+		pf := &rec.fields[frn-1] // here the index corresponds to the FRN
+
+		if err = skipItem(&c, pf, rec.fields); err != nil {
+			return c.len(), rec.decodeError(err, offset, pf.field)
+		}
+		rec.Spans = append(rec.Spans, Span{FRN: frn, Offset: offset, Len: c.pos - offset})
+
+		if cond := p.stdUAP.Condition; cond != nil && frn == cond.FRN {
+			var found bool
+			rec.fields, found = p.variant(c.data[offset:c.pos])
+			if !found {
+				return c.len(), rec.decodeError(ErrConditionUnknown, offset, pf.field)
+			}
+		}
+	}
+	rec.data = data[:c.pos:c.pos]
+	return c.len(), nil
+}
+
+// Payload returns the data of the record: FSPEC + items.
+func (rec *LazyRecord) Payload() []byte {
+	return rec.data
+}
+
+// Has returns true if the item frn is present in the record.
+func (rec *LazyRecord) Has(frn uint8) bool {
+	return rec.index(frn) >= 0
+}
+
+// Span returns the position of the item frn in the record.
+func (rec *LazyRecord) Span(frn uint8) (Span, bool) {
+	i := rec.index(frn)
+	if i < 0 {
+		return Span{}, false
+	}
+	return rec.Spans[i], true
+}
+
+// Meta returns the metadata of the item frn.
+func (rec *LazyRecord) Meta(frn uint8) (MetaItem, bool) {
+	if !rec.Has(frn) {
+		return MetaItem{}, false
+	}
+	return rec.fields[frn-1].meta, true
+}
+
+// Data returns the bytes of the item frn (its payload) without decoding it.
+func (rec *LazyRecord) Data(frn uint8) ([]byte, bool) {
+	i := rec.index(frn)
+	if i < 0 {
+		return nil, false
+	}
+	span := rec.Spans[i]
+	return rec.data[span.Offset : span.Offset+span.Len : span.Offset+span.Len], true
+}
+
+// Item decodes the item frn, it returns ErrItemNotFound if the item is absent.
+// The item is decoded by the first call and kept by the record: the next calls return the same Item,
+// its data aliases the data of the record.
+func (rec *LazyRecord) Item(frn uint8) (*Item, error) {
+	i := rec.index(frn)
+	if i < 0 {
+		return nil, ErrItemNotFound
+	}
+	return rec.item(i)
+}
+
+// Record decodes all the items, it returns the Record decoded from the data of the LazyRecord.
+func (rec *LazyRecord) Record() (*Record, error) {
+	tmp := NewRecord()
+	tmp.Cat = rec.Cat
+	tmp.Fspec = rec.Fspec
+	tmp.Items = make([]Item, 0, len(rec.Spans))
+	for _, span := range rec.Spans {
+		var item Item
+		c := cursor{data: rec.data, pos: span.Offset}
+		pf := &rec.fields[span.FRN-1]
+		if err := tmp.readItem(&c, pf, rec.fields, &item); err != nil {
+			return tmp, tmp.decodeError(err, span.Offset, pf.field)
+		}
+		tmp.Items = append(tmp.Items, item)
+	}
+	return tmp, nil
+}
+
+// index returns the index of the span of the item frn, -1 if absent.
+func (rec *LazyRecord) index(frn uint8) int {
+	for i := range rec.Spans {
+		if rec.Spans[i].FRN == frn {
+			return i
+		}
+	}
+	return -1
+}
+
+// item returns the item of the span i, it is decoded by its first call.
+func (rec *LazyRecord) item(i int) (*Item, error) {
+	if rec.items == nil {
+		rec.items = make([]*Item, len(rec.Spans))
+	}
+	if rec.items[i] != nil {
+		return rec.items[i], nil
+	}
+	if rec.storage == nil {
+		rec.storage = NewRecord()
+		rec.storage.Cat = rec.Cat
+	}
+	span := rec.Spans[i]
+	pf := &rec.fields[span.FRN-1]
+	item := &Item{}
+	c := cursor{data: rec.data, pos: span.Offset}
+	if err := rec.storage.readItem(&c, pf, rec.fields, item); err != nil {
+		return nil, rec.decodeError(err, span.Offset, pf.field)
+	}
+	rec.items[i] = item
+	return item, nil
+}
+
+// decodeError returns err located on the data field starting at offset of the record.
+func (rec *LazyRecord) decodeError(err error, offset int, field uap.DataField) *DecodeError {
+	return &DecodeError{
+		Offset:   offset,
+		Category: rec.Cat,
+		FRN:      field.FRN,
+		DataItem: field.DataItem,
+		Err:      err,
+	}
+}
+
+// LazyDataBlock is a DataBlock whose records are LazyRecord.
+// Raw contains the undecoded records of a data block of unknown category (see WithRawUnknown).
+type LazyDataBlock struct {
+	Category uint8
+	Len      uint16
+	Records  []*LazyRecord
+	Raw      []byte
+}
+
+func NewLazyDataBlock() *LazyDataBlock {
+	return &LazyDataBlock{}
+}
+
+// Decode locates the records of an asterix data block and their items without decoding them,
+// with the default Decoder (uap.DefaultProfiles). It returns the number of bytes unRead and
+// the errors of DataBlock.Decode.
+func (db *LazyDataBlock) Decode(data []byte) (int, error) {
+	return db.decode(data, defaultDecoder)
+}
+
+func (db *LazyDataBlock) decode(data []byte, d *Decoder) (int, error) {
+	c := cursor{data: data}
+
+	// retrieve category field
+	var err error
+	db.Category, err = c.byte()
+	if err != nil {
+		return c.len(), err // err = io.EOF
+	}
+
+	// retrieve length field
+	length, err := c.next(2)
+	if err != nil {
+		return c.len(), err
+	}
+	db.Len = uint16(length[0])<<8 + uint16(length[1])
+	if db.Len < 3 {
+		return c.len(), ErrLenInvalid
+	}
+	if len(data) < int(db.Len) {
+		return c.len(), ErrUndersized
+	}
+
+	// retrieve records, the spans are located in tmp
+	tmp := data[3:db.Len:db.Len]
+	if !d.alias {
+		tmp = make([]byte, db.Len-3)
+		copy(tmp, data[3:db.Len])
+	}
+	unRead := len(data) - int(db.Len)
+
+	if _, found := d.profiles[db.Category]; !found && d.resolver == nil {
+		if d.raw {
+			db.Raw = tmp
+			return unRead, nil
+		}
+		return unRead, ErrCategoryUnknown
+	}
+
+	offset := 0
+	var p *Plan
+	for offset < len(tmp) || len(db.Records) == 0 {
+		var found bool
+		p, found = d.recordPlan(db.Category, tmp[offset:], p)
+		if !found && d.raw {
+			db.Records = nil
+			db.Raw = tmp
+			return unRead, nil
+		}
+		if !found {
+			return unRead, ErrCategoryUnknown
+		}
+
+		rec := NewLazyRecord()
+		left, err := rec.decode(tmp[offset:], p)
+		db.Records = append(db.Records, rec)
+		if err != nil {
+			var decodeErr *DecodeError
+			if errors.As(err, &decodeErr) {
+				decodeErr.Offset += 3 + offset // from the CAT field
+				decodeErr.Record = len(db.Records) - 1
+			}
+			return unRead, err
+		}
+		offset = len(tmp) - left
+	}
+	return unRead, nil
+}
+
+// DecodeLazy locates the records of an asterix data block and their items without decoding them.
+// It returns the LazyDataBlock and the number of bytes unRead, see LazyDataBlock.Decode.
+func (d *Decoder) DecodeLazy(data []byte) (*LazyDataBlock, int, error) {
+	db := NewLazyDataBlock()
+	unRead, err := db.decode(data, d)
+	return db, unRead, err
+}
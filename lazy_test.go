@@ -0,0 +1,240 @@
+package goasterix
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/mokhtarimokhtar/goasterix/uap"
+	"github.com/mokhtarimokhtar/goasterix/util"
+)
+
+func TestLazyRecordDecode(t *testing.T) {
+	// setup
+	expansion := uap.Cat4Test
+	expansion.ReservedExpansion = []uap.DataField{
+		{FRN: 1, DataItem: "Sub/001", Type: uap.Fixed, Fixed: uap.FixedField{Size: 1}},
+		{FRN: 2, DataItem: "Sub/002", Type: uap.Explicit},
+	}
+	type dataTest struct {
+		TestCaseName string
+		input        string
+		uap          uap.StandardUAP
+		nbOfItems    int
+		err          error
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "CAT034",
+			input:        "f6083602429b7110940028200094008000",
+			uap:          uap.Cat034V127,
+			nbOfItems:    6,
+			err:          nil,
+		},
+		{
+			TestCaseName: "CAT048 with compound and BDS",
+			input:        "ffdf029319378d3da2056f132d0fff00946002de506f844cc3c35123310017013b026c000c74a74020a0 ff",
+			uap:          uap.Cat048V127,
+			nbOfItems:    14,
+			err:          nil,
+		},
+		{
+			TestCaseName: "all types of data field",
+			input:        "fd 40 ffff fffffe 03ffff 02ffffffff ab80 ff fffe 02ffffffff 04ffffff ffff 0101ffff 03ffff",
+			uap:          uap.Cat4Test,
+			nbOfItems:    7,
+			err:          nil,
+		},
+		{
+			TestCaseName: "conditional UAP",
+			input:        "01 38 80ff ffff",
+			uap:          uap.Cat4Test,
+			nbOfItems:    3,
+			err:          nil,
+		},
+		{
+			TestCaseName: "RE with sub-profile",
+			input:        "0180 06 c0 aa 03bbcc",
+			uap:          expansion,
+			nbOfItems:    1,
+			err:          nil,
+		},
+		{
+			TestCaseName: "RE not matching its sub-profile",
+			input:        "0180 04 c0 aa 03",
+			uap:          expansion,
+			nbOfItems:    0,
+			err:          ErrExpansionInvalid,
+		},
+		{
+			TestCaseName: "truncated item",
+			input:        "f6083602429b71109400282000940080",
+			uap:          uap.Cat034V127,
+			nbOfItems:    5,
+			err:          io.EOF,
+		},
+	}
+
+	for _, row := range dataSet {
+		// Arrange
+		data, _ := util.HexStringToByte(row.input)
+		expected := NewRecord()
+		expectedUnRead, expectedErr := expected.Decode(data, row.uap)
+		rec := NewLazyRecord()
+
+		// Act
+		unRead, err := rec.Decode(data, row.uap)
+
+		// Assert
+		var decodeErr, expectedDecodeErr *DecodeError
+		if !errors.Is(err, row.err) || unRead != expectedUnRead || len(rec.Spans) != row.nbOfItems {
+			t.Errorf("FAIL: %s - error = %v, unRead = %v, nbOfItems = %v; Expected: %v, %v, %v",
+				row.TestCaseName, err, unRead, len(rec.Spans), row.err, expectedUnRead, row.nbOfItems)
+			continue
+		}
+		if err != nil {
+			if !errors.As(err, &decodeErr) || !errors.As(expectedErr, &expectedDecodeErr) || *decodeErr != *expectedDecodeErr {
+				t.Errorf("FAIL: %s - error = %v; Expected: %v", row.TestCaseName, err, expectedErr)
+			} else {
+				t.Logf("SUCCESS: %s - error = %v; Expected: %v", row.TestCaseName, err, expectedErr)
+			}
+			continue
+		}
+		full, err := rec.Record()
+		if err != nil || !bytes.Equal(full.Payload(), expected.Payload()) || !bytes.Equal(rec.Payload(), data[:len(data)-unRead]) {
+			t.Errorf("FAIL: %s - Record() = %x, %v; Expected: %x", row.TestCaseName, full.Payload(), err, expected.Payload())
+			continue
+		}
+		offset := len(rec.Fspec)
+		for i, span := range rec.Spans {
+			item, err := rec.Item(span.FRN)
+			meta, _ := rec.Meta(span.FRN)
+			if err != nil || span.Offset != offset || item.Meta != expected.Items[i].Meta || meta != item.Meta ||
+				!bytes.Equal(item.Payload(), expected.Items[i].Payload()) {
+				t.Errorf("FAIL: %s - span = %v, item = %v, error = %v; Expected: %v",
+					row.TestCaseName, span, item, err, expected.Items[i].String())
+			}
+			offset += span.Len
+		}
+		t.Logf("SUCCESS: %s - nbOfItems = %v, unRead = %v", row.TestCaseName, len(rec.Spans), unRead)
+	}
+}
+
+func TestLazyRecord_Item(t *testing.T) {
+	// Arrange
+	data, _ := util.HexStringToByte("f6083602429b7110940028200094008000")
+	rec := NewLazyRecord()
+	_, _ = rec.Decode(data, uap.Cat034V127)
+
+	// Act
+	item, err := rec.Item(3)
+	span, found := rec.Span(3)
+	payload, _ := rec.Data(3)
+	_, errAbsent := rec.Item(5)
+
+	// Assert
+	if err != nil || item.Meta.DataItem != "I034/030" || !bytes.Equal(item.Fixed.Data, []byte{0x42, 0x9b, 0x71}) {
+		t.Errorf("FAIL: item = %v, error = %v; Expected: I034/030: 429b71", item, err)
+	}
+	if !found || span.Offset != 4 || span.Len != 3 || !bytes.Equal(payload, []byte{0x42, 0x9b, 0x71}) {
+		t.Errorf("FAIL: span = %v, data = %x; Expected: offset 4, len 3, data 429b71", span, payload)
+	}
+	if errAbsent != ErrItemNotFound || rec.Has(5) || !rec.Has(1) {
+		t.Errorf("FAIL: error = %v; Expected: %v", errAbsent, ErrItemNotFound)
+	} else {
+		t.Logf("SUCCESS: item = %v, span = %v, absent: %v", item.String(), span, errAbsent)
+	}
+}
+
+func TestLazyRecord_ItemAllocs(t *testing.T) {
+	// Arrange
+	data, _ := util.HexStringToByte("f6083602429b7110940028200094008000")
+	rec := NewLazyRecord()
+	_, _ = rec.Decode(data, uap.Cat034V127)
+	first, _ := rec.Item(6)
+
+	// Act
+	var item *Item
+	allocs := testing.AllocsPerRun(100, func() {
+		item, _ = rec.Item(6)
+	})
+
+	// Assert
+	if allocs != 0 || item != first {
+		t.Errorf("FAIL: allocs = %v, item = %p; Expected: %v, %p", allocs, item, 0, first)
+	} else {
+		t.Logf("SUCCESS: allocs = %v, item = %v", allocs, item.String())
+	}
+}
+
+func TestLazyDataBlockDecode(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		input        string
+		nbOfRecords  int
+		err          error
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "CAT048 5 records",
+			input:        "300118fff7020836429b52a094c70181091302d06002b7490d0138a178cf422002e79a5d27a00c0060a3280030a4000040063a0743ce5b4020f5fff7020836429b54e000bc020901a2005c7802e800263946e50464b1cb6ca0029ea9491062a4546093880032d4000040059602f639590220f5fff7020836429b58a0909703ff026405a26002bb4066740815f6e795e002e56a0530ffdff860b0d80032fc00004003cf0810c9ef4020fdfff7020836429b56a0775d03700ec205786002be4060910815f9c363a002a49a0f30bfffff60c4600030a4000040057207674a004020fdfff7020836429b55a0468c029804b105786002c57101124d6070d3282002adfa3333a0140060c4600030a4000040026e07d75fc04020f5",
+			nbOfRecords:  5,
+			err:          nil,
+		},
+		{
+			TestCaseName: "record error",
+			input:        "220014f6083602429b7110940028200094008000 220008f608360242",
+			nbOfRecords:  1,
+			err:          nil,
+		},
+		{
+			TestCaseName: "truncated record",
+			input:        "220008f608360242",
+			nbOfRecords:  1,
+			err:          io.ErrUnexpectedEOF,
+		},
+		{
+			TestCaseName: "category unknown",
+			input:        "100004ff",
+			nbOfRecords:  0,
+			err:          ErrCategoryUnknown,
+		},
+		{
+			TestCaseName: "undersized",
+			input:        "220014f6",
+			nbOfRecords:  0,
+			err:          ErrUndersized,
+		},
+	}
+
+	for _, row := range dataSet {
+		// Arrange
+		data, _ := util.HexStringToByte(row.input)
+		expected := NewDataBlock()
+		expectedUnRead, expectedErr := expected.Decode(data)
+
+		// Act
+		db, unRead, err := NewDecoder().DecodeLazy(data)
+
+		// Assert
+		if !errors.Is(err, row.err) || !errors.Is(expectedErr, row.err) || unRead != expectedUnRead ||
+			len(db.Records) != row.nbOfRecords || db.Len != expected.Len {
+			t.Errorf("FAIL: %s - error = %v, unRead = %v, nbOfRecords = %v; Expected: %v, %v, %v",
+				row.TestCaseName, err, unRead, len(db.Records), row.err, expectedUnRead, row.nbOfRecords)
+			continue
+		}
+		var decodeErr, expectedDecodeErr *DecodeError
+		if errors.As(err, &decodeErr) && (!errors.As(expectedErr, &expectedDecodeErr) || *decodeErr != *expectedDecodeErr) {
+			t.Errorf("FAIL: %s - error = %v; Expected: %v", row.TestCaseName, err, expectedErr)
+			continue
+		}
+		for i, rec := range db.Records {
+			if err == nil && !bytes.Equal(rec.Payload(), expected.Records[i].Payload()) {
+				t.Errorf("FAIL: %s - record %d = %x; Expected: %x", row.TestCaseName, i, rec.Payload(), expected.Records[i].Payload())
+			}
+		}
+		t.Logf("SUCCESS: %s - nbOfRecords = %v, error = %v", row.TestCaseName, len(db.Records), err)
+	}
+}
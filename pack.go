@@ -0,0 +1,131 @@
+package goasterix
+
+// maxDataBlockSize is the maximum size of a data block, limited by its LEN field.
+const maxDataBlockSize = 0xffff
+
+// Pack encodes the records of the DataBlock into one or more data blocks of at most maxSize bytes each
+// (CAT + LEN + records), e.g. 1472 bytes for the payload of an UDP datagram over Ethernet.
+// The LEN of each data block is computed, the records are not split and keep their order.
+// If maxSize <= 0 the data blocks are only limited by their LEN field (65535 bytes).
+// It returns ErrOversized if a record (or the records of a raw data block, see WithRawUnknown) does not fit
+// in a data block of maxSize bytes, ErrCategoryMismatch or ErrRecordEmpty like Encode.
+func (db *DataBlock) Pack(maxSize int) ([][]byte, error) {
+	pk := newPacker(maxSize)
+	pk.blockPerDatagram = true
+	if err := pk.addDataBlock(db); err != nil {
+		return nil, err
+	}
+	return pk.datagrams(), nil
+}
+
+// Pack encodes the data blocks of the WrapperDataBlock into datagrams of at most maxSize bytes each,
+// a datagram contains the data blocks of one or more categories (see WrapperDataBlock.Decode).
+// A data block which does not fit in the rest of a datagram is split on its records, the next records are
+// encoded in a new data block of the same category in the next datagram. The order of the records is kept.
+// If maxSize <= 0 the data blocks are encoded in one datagram. The errors are those of DataBlock.Pack.
+func (w *WrapperDataBlock) Pack(maxSize int) ([][]byte, error) {
+	pk := newPacker(maxSize)
+	for _, db := range w.DataBlocks {
+		if err := pk.addDataBlock(db); err != nil {
+			return nil, err
+		}
+	}
+	return pk.datagrams(), nil
+}
+
+// packer encodes records into data blocks and datagrams of limited size.
+type packer struct {
+	maxSize          int  // maximum size of a datagram, 0 without limit
+	blockPerDatagram bool // a datagram contains only one data block
+	done             [][]byte
+	cur              []byte // current datagram
+	header           int    // offset of the current data block in cur, -1 if none
+}
+
+func newPacker(maxSize int) *packer {
+	if maxSize < 0 {
+		maxSize = 0
+	}
+	return &packer{maxSize: maxSize, header: -1}
+}
+
+// addDataBlock encodes the records of db in a new data block, split over several datagrams if needed.
+func (pk *packer) addDataBlock(db *DataBlock) error {
+	if db.Raw != nil {
+		// the records of a raw data block are unknown: it is not split
+		pk.closeBlock()
+		if err := pk.addRecord(db.Category, db.Raw); err != nil {
+			return err
+		}
+		pk.closeBlock()
+		return nil
+	}
+	if len(db.Records) == 0 {
+		return ErrRecordEmpty
+	}
+	pk.closeBlock()
+	for _, rec := range db.Records {
+		if rec.Cat != db.Category {
+			return ErrCategoryMismatch
+		}
+		if len(rec.Items) == 0 {
+			return ErrRecordEmpty
+		}
+		if err := pk.addRecord(db.Category, rec.Payload()); err != nil {
+			return err
+		}
+	}
+	pk.closeBlock()
+	return nil
+}
+
+// addRecord appends a record to the current data block, or to a new data block (and datagram) if it does not fit.
+func (pk *packer) addRecord(category uint8, payload []byte) error {
+	if 3+len(payload) > maxDataBlockSize || (pk.maxSize != 0 && 3+len(payload) > pk.maxSize) {
+		return ErrOversized
+	}
+	if pk.header >= 0 && pk.fits(len(payload)) && len(pk.cur)-pk.header+len(payload) <= maxDataBlockSize {
+		pk.cur = append(pk.cur, payload...)
+		return nil
+	}
+
+	pk.closeBlock()
+	if len(pk.cur) != 0 && (pk.blockPerDatagram || !pk.fits(3+len(payload))) {
+		pk.flush()
+	}
+	pk.header = len(pk.cur)
+	pk.cur = append(pk.cur, category, 0, 0)
+	pk.cur = append(pk.cur, payload...)
+	return nil
+}
+
+// fits returns true if n bytes fit in the rest of the current datagram.
+func (pk *packer) fits(n int) bool {
+	return pk.maxSize == 0 || len(pk.cur)+n <= pk.maxSize
+}
+
+// closeBlock writes the LEN of the current data block.
+func (pk *packer) closeBlock() {
+	if pk.header < 0 {
+		return
+	}
+	length := len(pk.cur) - pk.header
+	pk.cur[pk.header+1] = byte(length >> 8)
+	pk.cur[pk.header+2] = byte(length & 0xff)
+	pk.header = -1
+}
+
+// flush ends the current datagram.
+func (pk *packer) flush() {
+	pk.closeBlock()
+	pk.done = append(pk.done, pk.cur)
+	pk.cur = nil
+}
+
+// datagrams returns the datagrams encoded.
+func (pk *packer) datagrams() [][]byte {
+	if len(pk.cur) != 0 {
+		pk.flush()
+	}
+	return pk.done
+}
@@ -0,0 +1,196 @@
+package goasterix
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/mokhtarimokhtar/goasterix/util"
+)
+
+// packInput is a CAT048 data block of 5 records (280 bytes) followed by a CAT034 data block of 1 record (20 bytes).
+const packInput = "300118fff7020836429b52a094c70181091302d06002b7490d0138a178cf422002e79a5d27a00c0060a3280030a4000040063a0743ce5b4020f5fff7020836429b54e000bc020901a2005c7802e800263946e50464b1cb6ca0029ea9491062a4546093880032d4000040059602f639590220f5fff7020836429b58a0909703ff026405a26002bb4066740815f6e795e002e56a0530ffdff860b0d80032fc00004003cf0810c9ef4020fdfff7020836429b56a0775d03700ec205786002be4060910815f9c363a002a49a0f30bfffff60c4600030a4000040057207674a004020fdfff7020836429b55a0468c029804b105786002c57101124d6070d3282002adfa3333a0140060c4600030a4000040026e07d75fc04020f5" +
+	"220014f6083602429b7110940028200094008000"
+
+// packedRecords returns the payloads of the records of datagrams, it fails if a datagram is not valid.
+func packedRecords(t *testing.T, datagrams [][]byte, maxSize int) [][]byte {
+	var records [][]byte
+	for _, datagram := range datagrams {
+		if maxSize > 0 && len(datagram) > maxSize {
+			t.Errorf("FAIL: datagram of %d bytes; Expected: <= %d", len(datagram), maxSize)
+		}
+		w, _ := NewWrapperDataBlock()
+		if _, err := w.Decode(datagram); err != nil {
+			t.Errorf("FAIL: datagram %x: error = %v; Expected: %v", datagram, err, nil)
+			return records
+		}
+		for _, db := range w.DataBlocks {
+			for _, rec := range db.Records {
+				records = append(records, rec.Payload())
+			}
+		}
+	}
+	return records
+}
+
+func TestDataBlockPack(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		maxSize      int
+		nbOfBlocks   int
+		err          error
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "without limit",
+			maxSize:      0,
+			nbOfBlocks:   1,
+			err:          nil,
+		},
+		{
+			TestCaseName: "UDP payload",
+			maxSize:      1472,
+			nbOfBlocks:   1,
+			err:          nil,
+		},
+		{
+			TestCaseName: "two records per data block",
+			maxSize:      120,
+			nbOfBlocks:   3,
+			err:          nil,
+		},
+		{
+			TestCaseName: "record larger than maxSize",
+			maxSize:      50,
+			nbOfBlocks:   0,
+			err:          ErrOversized,
+		},
+	}
+	data, _ := util.HexStringToByte(packInput)
+	db := NewDataBlock()
+	_, _ = db.Decode(data)
+	var expected [][]byte
+	for _, rec := range db.Records {
+		expected = append(expected, rec.Payload())
+	}
+
+	for _, row := range dataSet {
+		// Act
+		blocks, err := db.Pack(row.maxSize)
+
+		// Assert
+		if !errors.Is(err, row.err) || len(blocks) != row.nbOfBlocks {
+			t.Errorf("FAIL: %s - error = %v, nbOfBlocks = %v; Expected: %v, %v", row.TestCaseName, err, len(blocks), row.err, row.nbOfBlocks)
+			continue
+		}
+		if err != nil {
+			t.Logf("SUCCESS: %s - error = %v", row.TestCaseName, err)
+			continue
+		}
+		records := packedRecords(t, blocks, row.maxSize)
+		if !bytes.Equal(bytes.Join(records, nil), bytes.Join(expected, nil)) {
+			t.Errorf("FAIL: %s - records = %x; Expected: %x", row.TestCaseName, records, expected)
+		} else {
+			t.Logf("SUCCESS: %s - nbOfBlocks = %v", row.TestCaseName, len(blocks))
+		}
+	}
+	if blocks, _ := db.Pack(0); !bytes.Equal(blocks[0], data[:280]) {
+		t.Errorf("FAIL: data block = %x; Expected: %x", blocks[0], data[:280])
+	}
+}
+
+func TestWrapperDataBlockPack(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		maxSize      int
+		nbOfDatagram int
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "without limit",
+			maxSize:      0,
+			nbOfDatagram: 1,
+		},
+		{
+			TestCaseName: "UDP payload",
+			maxSize:      1472,
+			nbOfDatagram: 1,
+		},
+		{
+			TestCaseName: "CAT034 in the datagram of the last CAT048 records",
+			maxSize:      140,
+			nbOfDatagram: 3,
+		},
+		{
+			TestCaseName: "one record per datagram",
+			maxSize:      70,
+			nbOfDatagram: 6,
+		},
+	}
+	data, _ := util.HexStringToByte(packInput)
+	w, _ := NewWrapperDataBlock()
+	_, _ = w.Decode(data)
+	expected := packedRecords(t, [][]byte{data}, 0)
+
+	for _, row := range dataSet {
+		// Act
+		datagrams, err := w.Pack(row.maxSize)
+
+		// Assert
+		if err != nil || len(datagrams) != row.nbOfDatagram {
+			t.Errorf("FAIL: %s - error = %v, nbOfDatagram = %v; Expected: %v, %v", row.TestCaseName, err, len(datagrams), nil, row.nbOfDatagram)
+			continue
+		}
+		records := packedRecords(t, datagrams, row.maxSize)
+		if !bytes.Equal(bytes.Join(records, nil), bytes.Join(expected, nil)) {
+			t.Errorf("FAIL: %s - records = %x; Expected: %x", row.TestCaseName, records, expected)
+		} else {
+			t.Logf("SUCCESS: %s - nbOfDatagram = %v", row.TestCaseName, len(datagrams))
+		}
+	}
+	if datagrams, _ := w.Pack(0); !bytes.Equal(datagrams[0], data) {
+		t.Errorf("FAIL: datagram = %x; Expected: %x", datagrams[0], data)
+	}
+}
+
+func TestDataBlockPack_Invalid(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		db           *DataBlock
+		err          error
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "without record",
+			db:           &DataBlock{Category: 48},
+			err:          ErrRecordEmpty,
+		},
+		{
+			TestCaseName: "record without item",
+			db:           &DataBlock{Category: 48, Records: []*Record{{Cat: 48}}},
+			err:          ErrRecordEmpty,
+		},
+		{
+			TestCaseName: "record of another category",
+			db: &DataBlock{Category: 48, Records: []*Record{
+				{Cat: 34, Fspec: []byte{0x80}, Items: []Item{{Meta: MetaItem{FRN: 1}, Fixed: &Fixed{Data: []byte{0x08, 0x36}}}}},
+			}},
+			err: ErrCategoryMismatch,
+		},
+	}
+
+	for _, row := range dataSet {
+		// Act
+		_, err := row.db.Pack(1472)
+
+		// Assert
+		if err != row.err {
+			t.Errorf("FAIL: %s - error = %v; Expected: %v", row.TestCaseName, err, row.err)
+		} else {
+			t.Logf("SUCCESS: %s - error = %v", row.TestCaseName, err)
+		}
+	}
+}
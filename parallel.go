@@ -0,0 +1,139 @@
+package goasterix
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"runtime"
+	"sync"
+)
+
+// BlockResult is a data block decoded by Decoder.DecodeParallel.
+// Offset is the position in byte of the data block in the input, Err is the error of its decoding
+// (see DataBlock.Decode): DataBlock contains the records decoded until the error.
+type BlockResult struct {
+	Offset    int64
+	DataBlock *DataBlock
+	Err       error
+}
+
+// parallelJob is a data block to decode by a worker, its result is sent to res.
+type parallelJob struct {
+	offset int64
+	block  []byte
+	res    chan BlockResult
+}
+
+// DecodeParallel decodes the data blocks of r (e.g. a recording file) with a pool of workers goroutines,
+// runtime.GOMAXPROCS(0) if workers <= 0. The input is split on the data block boundaries using their LEN field.
+// fn is called by the calling goroutine for each data block in the order of the input, a decoding error of
+// a data block is reported in its BlockResult and the decoding goes on.
+// It stops and returns the error when fn returns an error, when ctx is done (ctx.Err()) or when the input
+// can not be split (ErrLenInvalid, io.ErrUnexpectedEOF when r ends inside a data block, or the error of r).
+// It returns nil when r ends on a data block boundary. The workers are ended when it returns, but a read of r
+// in progress is not waited for: the reading goroutine ends when the read returns, r must not be used until then
+// (e.g. close it to unblock the read).
+func (d *Decoder) DecodeParallel(ctx context.Context, r io.Reader, workers int, fn func(BlockResult) error) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	cctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// each block is read in its own buffer: the records can alias it
+	dd := *d
+	dd.alias = true
+
+	jobs := make(chan parallelJob, workers)
+	order := make(chan chan BlockResult, 2*workers) // results in the order of the input
+	readErr := make(chan error, 1)                  // received once order is closed
+
+	go func() {
+		defer close(jobs)
+		defer close(order)
+		readErr <- splitBlocks(cctx, r, jobs, order)
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-cctx.Done():
+					return
+				case job, ok := <-jobs:
+					if !ok {
+						return
+					}
+					res := BlockResult{Offset: job.offset}
+					if cctx.Err() == nil {
+						res.DataBlock = NewDataBlock()
+						_, res.Err = res.DataBlock.decode(job.block, &dd)
+					}
+					job.res <- res
+				}
+			}
+		}()
+	}
+
+	err := func() error {
+		for res := range order {
+			if ctx.Err() != nil { // a result may be ready too, the cancellation has priority
+				return ctx.Err()
+			}
+			select {
+			case <-cctx.Done():
+				return ctx.Err()
+			case result := <-res:
+				if err := fn(result); err != nil {
+					return err
+				}
+			}
+		}
+		return <-readErr
+	}()
+	cancel()
+	wg.Wait()
+
+	if err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// splitBlocks reads the data blocks of r and sends them to the workers (jobs) and their result channel to order.
+// It returns nil at the end of r on a data block boundary.
+func splitBlocks(ctx context.Context, r io.Reader, jobs chan<- parallelJob, order chan<- chan BlockResult) error {
+	br := bufio.NewReader(r)
+	buf := make([]byte, 0xffff)
+	var offset int64
+	for {
+		block, n, err := readBlock(br, buf)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		job := parallelJob{
+			offset: offset,
+			block:  append([]byte(nil), block...),
+			res:    make(chan BlockResult, 1),
+		}
+		offset += int64(n)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case order <- job.res:
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case jobs <- job:
+		}
+	}
+}
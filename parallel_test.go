@@ -0,0 +1,231 @@
+package goasterix
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/mokhtarimokhtar/goasterix/util"
+)
+
+// parallelInput returns n times the data blocks CAT048 (5 records) + CAT034 (1 record).
+func parallelInput(n int) []byte {
+	data, _ := util.HexStringToByte("300118fff7020836429b52a094c70181091302d06002b7490d0138a178cf422002e79a5d27a00c0060a3280030a4000040063a0743ce5b4020f5fff7020836429b54e000bc020901a2005c7802e800263946e50464b1cb6ca0029ea9491062a4546093880032d4000040059602f639590220f5fff7020836429b58a0909703ff026405a26002bb4066740815f6e795e002e56a0530ffdff860b0d80032fc00004003cf0810c9ef4020fdfff7020836429b56a0775d03700ec205786002be4060910815f9c363a002a49a0f30bfffff60c4600030a4000040057207674a004020fdfff7020836429b55a0468c029804b105786002c57101124d6070d3282002adfa3333a0140060c4600030a4000040026e07d75fc04020f5" +
+		"220014f6083602429b7110940028200094008000")
+	return bytes.Repeat(data, n)
+}
+
+func TestDecoderDecodeParallel(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		input        []byte
+		workers      int
+		nbOfBlocks   int
+		err          error
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "default workers",
+			input:        parallelInput(100),
+			workers:      0,
+			nbOfBlocks:   200,
+			err:          nil,
+		},
+		{
+			TestCaseName: "one worker",
+			input:        parallelInput(10),
+			workers:      1,
+			nbOfBlocks:   20,
+			err:          nil,
+		},
+		{
+			TestCaseName: "more workers than blocks",
+			input:        parallelInput(1),
+			workers:      8,
+			nbOfBlocks:   2,
+			err:          nil,
+		},
+		{
+			TestCaseName: "input ends inside a data block",
+			input:        append(parallelInput(3), 0x22, 0x00, 0x14, 0xf6),
+			workers:      4,
+			nbOfBlocks:   6,
+			err:          io.ErrUnexpectedEOF,
+		},
+		{
+			TestCaseName: "empty",
+			input:        nil,
+			workers:      4,
+			nbOfBlocks:   0,
+			err:          nil,
+		},
+	}
+	d := NewDecoder()
+
+	for _, row := range dataSet {
+		// Arrange
+		var expected []BlockResult
+		r := d.NewReader(bytes.NewReader(row.input))
+		for {
+			offset := r.Offset()
+			db, err := r.Next()
+			if db == nil {
+				break
+			}
+			expected = append(expected, BlockResult{Offset: offset, DataBlock: db, Err: err})
+		}
+		var results []BlockResult
+
+		// Act
+		err := d.DecodeParallel(context.Background(), bytes.NewReader(row.input), row.workers, func(res BlockResult) error {
+			results = append(results, res)
+			return nil
+		})
+
+		// Assert
+		if !errors.Is(err, row.err) {
+			t.Errorf("FAIL: %s - error = %v; Expected: %v", row.TestCaseName, err, row.err)
+		}
+		if len(results) != row.nbOfBlocks || len(expected) != row.nbOfBlocks {
+			t.Errorf("FAIL: %s - nbOfBlocks = %v; Expected: %v", row.TestCaseName, len(results), row.nbOfBlocks)
+			continue
+		}
+		for i, res := range results {
+			exp := expected[i]
+			if res.Offset != exp.Offset || res.Err != exp.Err || res.DataBlock.Category != exp.DataBlock.Category ||
+				!bytes.Equal(bytes.Join(res.DataBlock.Payload(), nil), bytes.Join(exp.DataBlock.Payload(), nil)) {
+				t.Errorf("FAIL: %s - block %d at offset %d; Expected: offset %d", row.TestCaseName, i, res.Offset, exp.Offset)
+				break
+			}
+		}
+		t.Logf("SUCCESS: %s - nbOfBlocks = %v, error = %v", row.TestCaseName, len(results), err)
+	}
+}
+
+func TestDecoderDecodeParallel_DecodeError(t *testing.T) {
+	// Arrange
+	input, _ := util.HexStringToByte("220014f6083602429b7110940028200094008000 220008f608360242 220014f6083602429b7110940028200094008000")
+	var results []BlockResult
+
+	// Act
+	err := NewDecoder().DecodeParallel(context.Background(), bytes.NewReader(input), 2, func(res BlockResult) error {
+		results = append(results, res)
+		return nil
+	})
+
+	// Assert
+	if err != nil || len(results) != 3 {
+		t.Fatalf("FAIL: error = %v, nbOfBlocks = %v; Expected: %v, %v", err, len(results), nil, 3)
+	}
+	var decodeErr *DecodeError
+	if results[0].Err != nil || !errors.As(results[1].Err, &decodeErr) || results[2].Err != nil || results[1].Offset != 20 {
+		t.Errorf("FAIL: errors = %v, %v, %v; Expected: %v, DecodeError at offset 20, %v",
+			results[0].Err, results[1].Err, results[2].Err, nil, nil)
+	} else {
+		t.Logf("SUCCESS: error = %v at offset %v", results[1].Err, results[1].Offset)
+	}
+}
+
+func TestDecoderDecodeParallel_Stop(t *testing.T) {
+	// setup
+	errStop := errors.New("stop")
+	type dataTest struct {
+		TestCaseName string
+		fn           func(cancel context.CancelFunc, n int) error
+		nbOfBlocks   int
+		err          error
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "callback error",
+			fn: func(cancel context.CancelFunc, n int) error {
+				if n == 3 {
+					return errStop
+				}
+				return nil
+			},
+			nbOfBlocks: 3,
+			err:        errStop,
+		},
+		{
+			TestCaseName: "context canceled",
+			fn: func(cancel context.CancelFunc, n int) error {
+				if n == 5 {
+					cancel()
+				}
+				return nil
+			},
+			nbOfBlocks: 5,
+			err:        context.Canceled,
+		},
+	}
+
+	for _, row := range dataSet {
+		// Arrange
+		ctx, cancel := context.WithCancel(context.Background())
+		n := 0
+
+		// Act
+		err := NewDecoder().DecodeParallel(ctx, bytes.NewReader(parallelInput(500)), 4, func(res BlockResult) error {
+			n++
+			return row.fn(cancel, n)
+		})
+		cancel()
+
+		// Assert
+		if !errors.Is(err, row.err) || n != row.nbOfBlocks {
+			t.Errorf("FAIL: %s - error = %v, nbOfBlocks = %v; Expected: %v, %v", row.TestCaseName, err, n, row.err, row.nbOfBlocks)
+		} else {
+			t.Logf("SUCCESS: %s - error = %v, nbOfBlocks = %v", row.TestCaseName, err, n)
+		}
+	}
+}
+
+// blockedReader returns data, then blocks until release is closed.
+type blockedReader struct {
+	data    []byte
+	release chan struct{}
+}
+
+func (b *blockedReader) Read(p []byte) (int, error) {
+	if len(b.data) == 0 {
+		<-b.release
+		return 0, io.EOF
+	}
+	n := copy(p, b.data)
+	b.data = b.data[n:]
+	return n, nil
+}
+
+func TestDecoderDecodeParallel_CancelBlockedRead(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := &blockedReader{data: parallelInput(1), release: make(chan struct{})}
+	defer close(r.release)
+	done := make(chan error, 1)
+
+	// Act
+	go func() {
+		done <- NewDecoder().DecodeParallel(ctx, r, 2, func(res BlockResult) error {
+			cancel()
+			return nil
+		})
+	}()
+
+	// Assert
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("FAIL: error = %v; Expected: %v", err, context.Canceled)
+		} else {
+			t.Logf("SUCCESS: error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("FAIL: DecodeParallel is blocked by the read in progress; Expected: return on cancel")
+	}
+}
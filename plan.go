@@ -0,0 +1,285 @@
+package goasterix
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/mokhtarimokhtar/goasterix/uap"
+)
+
+// Plan is a User Application Profile compiled for decoding: its data fields are indexed by FRN with their sizes,
+// the subfields of the Compound fields and the sub-profiles of the RE and SP fields are compiled as sub-plans
+// and the items of each variant of a conditional UAP are precomputed.
+// The decoding of a record with a Plan does not walk the UAP. A Plan is immutable, it can be used by several goroutines.
+type Plan struct {
+	key      planKey
+	stdUAP   uap.StandardUAP
+	items    []planField
+	variants map[uint8][]planField
+}
+
+// planField is a compiled data field.
+type planField struct {
+	field     uap.DataField // definition of the data field, the sub-profile of a RE or SP field included
+	meta      MetaItem      // metadata of the items decoded
+	size      int           // size of a Fixed field, primary size of an Extended field, subitem size of a Repetitive field
+	secondary int           // secondary size of an Extended field
+	sub       []planField   // subfields of a Compound field or sub-profile of a RE or SP field, indexed by FRN
+	skip      bool          // the items of the data field are not selected, see WithSelection
+}
+
+// Compile returns the decoding Plan of stdUAP. The plan is cached like the plans of Record.Decode.
+func Compile(stdUAP uap.StandardUAP) *Plan {
+	return profilePlan(stdUAP)
+}
+
+// compile returns the decoding Plan of stdUAP, the data fields not selected by sel (if not nil) are skipped.
+func compile(stdUAP uap.StandardUAP, sel *selection) *Plan {
+	p := &Plan{key: newPlanKey(stdUAP)}
+	stdUAP.Condition = stdUAP.EffectiveCondition()
+	p.stdUAP = stdUAP
+	p.items = compileFields(stdUAP.Items, stdUAP, sel)
+
+	if c := stdUAP.Condition; c != nil && int(c.FRN) <= len(stdUAP.Items) {
+		if c.FRN != 0 {
+			p.items[c.FRN-1].skip = false // the variant of the records depends on the discriminating item
+		}
+		p.variants = make(map[uint8][]planField, len(c.Variants))
+		for key, variant := range c.Variants {
+			fields := make([]planField, 0, int(c.FRN)+len(variant))
+			fields = append(fields, p.items[:c.FRN]...)
+			fields = append(fields, compileFields(variant, stdUAP, sel)...)
+			p.variants[key] = fields
+		}
+	}
+	return p
+}
+
+// UAP returns the User Application Profile compiled.
+func (p *Plan) UAP() uap.StandardUAP {
+	return p.stdUAP
+}
+
+// Fields returns the definitions of the data fields of a record decoded with the Plan, indexed by FRN-1:
+// the items of the variant selected by the discriminating item of a conditional UAP, if present,
+// and the RE and SP fields with the sub-profiles of the UAP.
+func (p *Plan) Fields(rec *Record) ([]uap.DataField, error) {
+	pfs, err := rec.fields(p)
+	if err != nil {
+		return nil, err
+	}
+	return dataFields(pfs), nil
+}
+
+// variant returns the items selected by the content of the discriminating item of a conditional UAP,
+// see uap.Condition.Select.
+func (p *Plan) variant(data []byte) ([]planField, bool) {
+	c := p.stdUAP.Condition
+	if int(c.Octet) >= len(data) {
+		return nil, false
+	}
+	fields, found := p.variants[data[c.Octet]&c.Mask]
+	return fields, found
+}
+
+// compileFields returns the compiled data fields, the RE and SP fields without sub-profile use those of stdUAP.
+// The data fields not selected by sel (if not nil) are skipped.
+func compileFields(fields []uap.DataField, stdUAP uap.StandardUAP, sel *selection) []planField {
+	pfs := make([]planField, len(fields))
+	for i, field := range fields {
+		field = expansionField(field, stdUAP)
+		pf := planField{field: field, meta: NewItem(field).Meta}
+		switch field.Type {
+		case uap.Fixed:
+			pf.size = int(field.Fixed.Size)
+		case uap.Extended:
+			pf.size = int(field.Extended.PrimarySize)
+			pf.secondary = int(field.Extended.SecondarySize)
+		case uap.Repetitive:
+			pf.size = int(field.Repetitive.SubItemSize)
+		case uap.Compound, uap.SP, uap.RE:
+			if len(field.Compound) != 0 {
+				pf.sub = compileFields(field.Compound, uap.StandardUAP{}, nil)
+			}
+		}
+		pf.skip = sel != nil && !sel.selected(&pf)
+		pfs[i] = pf
+	}
+	return pfs
+}
+
+// maxSharedPlans is the maximum number of plans of shared profiles cached by a planCache: beyond this number
+// (e.g. profiles created for each record) the cache is emptied.
+const maxSharedPlans = 256
+
+// planKey identifies a User Application Profile by the arrays of its data fields.
+type planKey struct {
+	category  uint8
+	name      string
+	items     *uap.DataField
+	nbOfItems int
+	condition *uap.Condition
+	re        *uap.DataField
+	sp        *uap.DataField
+}
+
+func newPlanKey(stdUAP uap.StandardUAP) planKey {
+	key := planKey{
+		category:  stdUAP.Category,
+		name:      stdUAP.Name,
+		nbOfItems: len(stdUAP.Items),
+		condition: stdUAP.Condition,
+	}
+	if len(stdUAP.Items) != 0 {
+		key.items = &stdUAP.Items[0]
+	}
+	if len(stdUAP.ReservedExpansion) != 0 {
+		key.re = &stdUAP.ReservedExpansion[0]
+	}
+	if len(stdUAP.SpecialPurpose) != 0 {
+		key.sp = &stdUAP.SpecialPurpose[0]
+	}
+	return key
+}
+
+// sharedPlan is the cached Plan of a shared profile with a copy of the profile compiled,
+// which detects the data fields of the profile modified in place.
+type sharedPlan struct {
+	stdUAP uap.StandardUAP
+	plan   *Plan
+}
+
+// planCache contains the plans compiled by a Decoder.
+// The plans of the profiles owned by the Decoder (copied by NewDecoder) are cached by category.
+// The plans of the shared profiles, uap.DefaultProfiles used by the package-level decoding, the profiles given
+// to Record.Decode or Record.Encode and the profiles selected by a ProfileResolver, are cached by planKey
+// and checked against the profile at each use: a profile modified in place is compiled again.
+type planCache struct {
+	owned      bool
+	plans      sync.Map             // category => *Plan
+	shared     sync.Map             // planKey => *sharedPlan
+	nbOfShared int32                // number of plans in shared
+	selections map[uint8]*selection // by category, see WithSelection
+}
+
+// registered returns the Plan of stdUAP, the profile registered in the Decoder for its category.
+// last is the Plan of the previous record of the data block (nil for the first record), see resolved.
+func (pc *planCache) registered(stdUAP uap.StandardUAP, last *Plan) *Plan {
+	if !pc.owned {
+		return pc.resolved(stdUAP, last)
+	}
+	if p, found := pc.plans.Load(stdUAP.Category); found {
+		return p.(*Plan)
+	}
+	p, _ := pc.plans.LoadOrStore(stdUAP.Category, compile(stdUAP, pc.selections[stdUAP.Category]))
+	return p.(*Plan)
+}
+
+// resolved returns the Plan of stdUAP, a profile which is not owned by the Decoder.
+// last (the Plan of the previous record of the data block, nil for the first record) is reused if it is
+// the plan of stdUAP, otherwise the cached plan is checked against stdUAP.
+func (pc *planCache) resolved(stdUAP uap.StandardUAP, last *Plan) *Plan {
+	key := newPlanKey(stdUAP)
+	if last != nil && last.key == key {
+		return last
+	}
+	if tmp, found := pc.shared.Load(key); found {
+		if sp := tmp.(*sharedPlan); sameProfile(sp.stdUAP, stdUAP) {
+			return sp.plan
+		}
+	}
+
+	sp := &sharedPlan{stdUAP: copyProfile(stdUAP)}
+	sp.plan = compile(sp.stdUAP, pc.selections[stdUAP.Category])
+	sp.plan.key = key
+	if atomic.AddInt32(&pc.nbOfShared, 1) > maxSharedPlans {
+		pc.shared.Range(func(k, _ interface{}) bool {
+			pc.shared.Delete(k)
+			return true
+		})
+		atomic.StoreInt32(&pc.nbOfShared, 1)
+	}
+	pc.shared.Store(key, sp)
+	return sp.plan
+}
+
+// profilePlan returns the cached Plan of a profile given to Record.Decode, LazyRecord.Decode or Record.Encode.
+func profilePlan(stdUAP uap.StandardUAP) *Plan {
+	return defaultDecoder.plans.resolved(stdUAP, nil)
+}
+
+// sameProfile returns true if the profiles a and b have the same definitions of data fields.
+// The Enum of the subfields, not used by the decoding, are not compared.
+func sameProfile(a, b uap.StandardUAP) bool {
+	if a.Category != b.Category || !sameFields(a.Items, b.Items) ||
+		!sameFields(a.ReservedExpansion, b.ReservedExpansion) || !sameFields(a.SpecialPurpose, b.SpecialPurpose) {
+		return false
+	}
+	if a.Condition == nil || b.Condition == nil {
+		return a.Condition == b.Condition
+	}
+	ca, cb := a.Condition, b.Condition
+	if ca.FRN != cb.FRN || ca.Octet != cb.Octet || ca.Mask != cb.Mask || len(ca.Variants) != len(cb.Variants) {
+		return false
+	}
+	for key, variant := range ca.Variants {
+		if tmp, found := cb.Variants[key]; !found || !sameFields(variant, tmp) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameFields(a, b []uap.DataField) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		x, y := &a[i], &b[i]
+		if x.FRN != y.FRN || x.Type != y.Type || x.Fixed != y.Fixed || x.Extended != y.Extended ||
+			x.Repetitive != y.Repetitive || x.Explicit != y.Explicit || x.Conditional != y.Conditional ||
+			x.DataItem != y.DataItem || x.Description != y.Description ||
+			!sameFields(x.Compound, y.Compound) || len(x.SubFields) != len(y.SubFields) {
+			return false
+		}
+		for j := range x.SubFields {
+			sx, sy := &x.SubFields[j], &y.SubFields[j]
+			if sx.Name != sy.Name || sx.From != sy.From || sx.To != sy.To || sx.Part != sy.Part ||
+				sx.Signed != sy.Signed || sx.Scale != sy.Scale || sx.Unit != sy.Unit || sx.Format != sy.Format {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// copyProfile returns a copy of stdUAP which does not share its data fields, the Enum of their subfields excepted.
+func copyProfile(stdUAP uap.StandardUAP) uap.StandardUAP {
+	stdUAP.Items = copyFields(stdUAP.Items)
+	stdUAP.ReservedExpansion = copyFields(stdUAP.ReservedExpansion)
+	stdUAP.SpecialPurpose = copyFields(stdUAP.SpecialPurpose)
+	if c := stdUAP.Condition; c != nil {
+		tmp := *c
+		tmp.Variants = make(map[uint8][]uap.DataField, len(c.Variants))
+		for key, variant := range c.Variants {
+			tmp.Variants[key] = copyFields(variant)
+		}
+		stdUAP.Condition = &tmp
+	}
+	return stdUAP
+}
+
+func copyFields(fields []uap.DataField) []uap.DataField {
+	if fields == nil {
+		return nil
+	}
+	tmp := make([]uap.DataField, len(fields))
+	for i, field := range fields {
+		field.Compound = copyFields(field.Compound)
+		if field.SubFields != nil {
+			field.SubFields = append([]uap.SubField(nil), field.SubFields...)
+		}
+		tmp[i] = field
+	}
+	return tmp
+}
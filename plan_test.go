@@ -0,0 +1,272 @@
+package goasterix
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mokhtarimokhtar/goasterix/uap"
+	"github.com/mokhtarimokhtar/goasterix/util"
+)
+
+func TestCompile(t *testing.T) {
+	// Arrange
+	stdUAP := uap.Cat4Test
+
+	// Act
+	p := Compile(stdUAP)
+
+	// Assert
+	if len(p.items) != len(stdUAP.Items) {
+		t.Fatalf("FAIL: nbOfItems = %v; Expected: %v", len(p.items), len(stdUAP.Items))
+	}
+	for i, pf := range p.items {
+		field := stdUAP.Items[i]
+		var size, secondary int
+		switch field.Type {
+		case uap.Fixed:
+			size = int(field.Fixed.Size)
+		case uap.Extended:
+			size, secondary = int(field.Extended.PrimarySize), int(field.Extended.SecondarySize)
+		case uap.Repetitive:
+			size = int(field.Repetitive.SubItemSize)
+		}
+		if pf.field.FRN != field.FRN || pf.size != size || pf.secondary != secondary {
+			t.Errorf("FAIL: FRN %d - size = %d, %d; Expected: %d, %d", field.FRN, pf.size, pf.secondary, size, secondary)
+		}
+		sub := field.Compound
+		if field.Type == uap.RE && len(sub) == 0 {
+			sub = stdUAP.ReservedExpansion
+		}
+		if field.Type == uap.SP && len(sub) == 0 {
+			sub = stdUAP.SpecialPurpose
+		}
+		if len(pf.sub) != len(sub) {
+			t.Errorf("FAIL: FRN %d - nbOfSubfields = %d; Expected: %d", field.FRN, len(pf.sub), len(sub))
+		}
+	}
+	t.Logf("SUCCESS: %d items compiled", len(p.items))
+}
+
+func TestCompile_Condition(t *testing.T) {
+	// Arrange
+	stdUAP := uap.Cat001V12
+
+	// Act
+	p := Compile(stdUAP)
+
+	// Assert
+	for key, variant := range stdUAP.Condition.Variants {
+		fields, found := p.variant([]byte{key})
+		if !found || len(fields) != int(stdUAP.Condition.FRN)+len(variant) {
+			t.Errorf("FAIL: variant %02x - nbOfItems = %d, found = %v; Expected: %d, %v",
+				key, len(fields), found, int(stdUAP.Condition.FRN)+len(variant), true)
+		} else {
+			t.Logf("SUCCESS: variant %02x - nbOfItems = %d", key, len(fields))
+		}
+	}
+	if _, found := p.variant(nil); found {
+		t.Errorf("FAIL: variant of empty data found")
+	}
+}
+
+func TestRecordDecodePlan(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		input        string
+		stdUAP       uap.StandardUAP
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "CAT048",
+			input:        "fff702 0836 429b52 a0 94c70181 0913 02d0 6002b7 490d01 38a178cf4220 02e79a5d27a00c0060a3280030a4000040 063a 0743ce5b 40 20f5",
+			stdUAP:       uap.Cat048V127,
+		},
+		{
+			TestCaseName: "CAT001 track",
+			input:        "f50208319801bf0a1ebb43022538e200",
+			stdUAP:       uap.Cat001V12,
+		},
+		{
+			TestCaseName: "Cat4Test",
+			input:        "fc ffff fffffe 03ffff 02ffffffff ab80 ff fffe 02ffffffff 04ffffff ffff 0101ffff",
+			stdUAP:       uap.Cat4Test,
+		},
+	}
+
+	for _, row := range dataSet {
+		// Arrange
+		data, _ := util.HexStringToByte(row.input)
+		output := NewRecord()
+		unReadOutput, errOutput := output.Decode(data, row.stdUAP)
+		rec := NewRecord()
+
+		// Act
+		unRead, err := rec.DecodePlan(data, Compile(row.stdUAP))
+
+		// Assert
+		if err != errOutput || unRead != unReadOutput {
+			t.Errorf("FAIL: %s - unRead = %v, error = %v; Expected: %v, %v", row.TestCaseName, unRead, err, unReadOutput, errOutput)
+		}
+		if !reflect.DeepEqual(rec.Items, output.Items) {
+			t.Errorf("FAIL: %s - items = %v; Expected: %v", row.TestCaseName, rec.String(), output.String())
+		} else {
+			t.Logf("SUCCESS: %s - items = %v", row.TestCaseName, rec.String())
+		}
+	}
+}
+
+func TestDecoderPlan_Cache(t *testing.T) {
+	// Arrange
+	d := NewDecoder()
+
+	// Act
+	p1, found1 := d.Plan(48)
+	p2, found2 := d.Plan(48)
+	_, found3 := d.Plan(0)
+
+	// Assert
+	if !found1 || !found2 || p1 != p2 {
+		t.Errorf("FAIL: plans = %p, %p; Expected: the same plan", p1, p2)
+	} else {
+		t.Logf("SUCCESS: plans = %p, %p", p1, p2)
+	}
+	if p1.UAP().Name != uap.Cat048V127.Name {
+		t.Errorf("FAIL: UAP = %s; Expected: %s", p1.UAP().Name, uap.Cat048V127.Name)
+	}
+	if found3 {
+		t.Errorf("FAIL: plan of unknown category found")
+	}
+}
+
+func TestPlanCache_Resolved(t *testing.T) {
+	// Arrange
+	pc := &planCache{}
+	stdUAP := uap.StandardUAP{Category: 26, Items: []uap.DataField{{FRN: 1, Type: uap.Fixed}}}
+	other := uap.StandardUAP{Category: 26, Items: []uap.DataField{{FRN: 1, Type: uap.Fixed}}}
+
+	// Act
+	p1 := pc.resolved(stdUAP, nil)
+	p2 := pc.resolved(stdUAP, p1)
+	p3 := pc.resolved(other, p2)
+	p4 := pc.resolved(stdUAP, nil)
+	stdUAP.Items[0].Fixed = uap.FixedField{Size: 2}
+	p5 := pc.resolved(stdUAP, nil)
+
+	// Assert
+	if p2 != p1 || p4 != p1 {
+		t.Errorf("FAIL: plans = %p, %p, %p; Expected: the cached plan", p1, p2, p4)
+	}
+	if p3 == p1 || p5 == p1 {
+		t.Errorf("FAIL: plans = %p, %p, %p; Expected: compiled plans", p1, p3, p5)
+	} else {
+		t.Logf("SUCCESS: plans = %p, %p, %p, %p, %p", p1, p2, p3, p4, p5)
+	}
+}
+
+func TestPlanCache_ResolvedLimit(t *testing.T) {
+	// Arrange
+	pc := &planCache{}
+
+	// Act
+	for i := 0; i < 2*maxSharedPlans; i++ {
+		stdUAP := uap.StandardUAP{Category: 26, Items: []uap.DataField{{FRN: 1, Type: uap.Fixed}}}
+		pc.resolved(stdUAP, nil)
+	}
+
+	// Assert
+	if pc.nbOfShared > maxSharedPlans {
+		t.Errorf("FAIL: nbOfShared = %v; Expected: <= %v", pc.nbOfShared, maxSharedPlans)
+	} else {
+		t.Logf("SUCCESS: nbOfShared = %v", pc.nbOfShared)
+	}
+}
+
+func TestRecordDecode_UAPModifiedInPlace(t *testing.T) {
+	// Arrange
+	stdUAP := uap.StandardUAP{Category: 26, Items: []uap.DataField{
+		{FRN: 1, DataItem: "I026/001", Type: uap.Fixed, Fixed: uap.FixedField{Size: 1}},
+		{FRN: 2, DataItem: "I026/002", Type: uap.Fixed, Fixed: uap.FixedField{Size: 1}},
+	}}
+	data1, _ := util.HexStringToByte("c0 01 02")
+	data2, _ := util.HexStringToByte("c0 0102 03")
+	rec := NewRecord()
+	if _, err := rec.Decode(data1, stdUAP); err != nil {
+		t.Fatalf("FAIL: error = %v; Expected: %v", err, nil)
+	}
+
+	// Act
+	stdUAP.Items[0].Fixed.Size = 2
+	rec = NewRecord()
+	unRead, err := rec.Decode(data2, stdUAP)
+
+	// Assert
+	if err != nil || unRead != 0 || len(rec.Items) != 2 || len(rec.Items[0].Fixed.Data) != 2 {
+		t.Errorf("FAIL: error = %v, unRead = %v, items = %v; Expected: %v, %v, [0102 03]", err, unRead, rec.String(), nil, 0)
+	} else {
+		t.Logf("SUCCESS: items = %v", rec.String())
+	}
+}
+
+func TestDecoder_ProfileModifiedInPlace(t *testing.T) {
+	// Arrange
+	stdUAP := uap.StandardUAP{Category: 26, Items: []uap.DataField{
+		{FRN: 1, DataItem: "I026/001", Type: uap.Fixed, Fixed: uap.FixedField{Size: 1}},
+		{FRN: 2, DataItem: "I026/002", Type: uap.Fixed, Fixed: uap.FixedField{Size: 1}},
+	}}
+	d := NewDecoder(WithProfile(stdUAP))
+	data, _ := util.HexStringToByte("1a 0006 c0 01 02")
+
+	// Act
+	stdUAP.Items[0].Fixed.Size = 2
+	db, unRead, err := d.DecodeDataBlock(data)
+
+	// Assert
+	if err != nil || unRead != 0 || len(db.Records) != 1 || len(db.Records[0].Items) != 2 {
+		t.Errorf("FAIL: error = %v, unRead = %v; Expected: %v, %v, the profile of the Decoder unchanged", err, unRead, nil, 0)
+	} else {
+		t.Logf("SUCCESS: items = %v", db.Records[0].String())
+	}
+}
+
+func TestPlanFields(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		input        string
+		frn          uint8
+		dataItem     string
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "plot variant",
+			input:        "f0 0831 00 0a8abb2e 3802",
+			frn:          3,
+			dataItem:     "I001/040",
+		},
+		{
+			TestCaseName: "track variant",
+			input:        "f502 0831 98 01bf 0a1ebb43 022538e2 00",
+			frn:          3,
+			dataItem:     "I001/161",
+		},
+	}
+	p := Compile(uap.Cat001V12)
+
+	for _, row := range dataSet {
+		// Arrange
+		data, _ := util.HexStringToByte(row.input)
+		rec := NewRecord()
+		_, _ = rec.DecodePlan(data, p)
+
+		// Act
+		fields, err := p.Fields(rec)
+
+		// Assert
+		if err != nil || int(row.frn) > len(fields) || fields[row.frn-1].DataItem != row.dataItem {
+			t.Errorf("FAIL: %s - error = %v, fields = %v; Expected: FRN %v %v", row.TestCaseName, err, len(fields), row.frn, row.dataItem)
+		} else {
+			t.Logf("SUCCESS: %s - FRN %v = %v", row.TestCaseName, row.frn, fields[row.frn-1].DataItem)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package goasterix
+
+import (
+	"bufio"
+	"errors"
+	"io"
+)
+
+var (
+	// ErrLenInvalid reports that a LEN field is smaller than the header it counts:
+	// CAT + LEN for a data block, the length indicator for an Explicit, SP or RE field.
+	ErrLenInvalid = errors.New("[ASTERIX] invalid data block length")
+)
+
+// Reader reads a stream of asterix data blocks (e.g. a recording file or a TCP feed) one DataBlock at a time.
+// Only one data block is kept in memory: the memory used does not depend on the size of the stream.
+// Stream = [CAT + LEN + RECORD + ...] + [ DATABLOCK ] + [...] + ...
+type Reader struct {
+	d      *Decoder
+	r      *bufio.Reader
+	buf    []byte
+	offset int64
+}
+
+// NewReader returns a new Reader reading from r with the default Decoder (uap.DefaultProfiles).
+func NewReader(r io.Reader) *Reader {
+	return defaultDecoder.NewReader(r)
+}
+
+// NewReader returns a new Reader reading from r with the profiles of the Decoder.
+func (d *Decoder) NewReader(r io.Reader) *Reader {
+	return &Reader{
+		d:   d,
+		r:   bufio.NewReader(r),
+		buf: make([]byte, 0xffff),
+	}
+}
+
+// Next reads and decodes the next data block of the stream.
+// It waits until the whole data block is received, the partial reads are handled by the Reader.
+// It returns io.EOF when the stream ends on a data block boundary and io.ErrUnexpectedEOF when the stream
+// ends inside a data block.
+// A decoding error of the records is returned with the DataBlock, the Reader is then positioned on the next
+// data block and Next can be called again.
+func (r *Reader) Next() (*DataBlock, error) {
+	block, n, err := readBlock(r.r, r.buf)
+	r.offset += int64(n)
+	if err != nil {
+		return nil, err
+	}
+
+	db := NewDataBlock()
+	_, err = db.decode(block, r.d)
+	return db, err
+}
+
+// readBlock reads the next data block of r into buf (0xffff bytes) and returns it with the number of bytes read.
+// It returns io.EOF when r ends on a data block boundary and io.ErrUnexpectedEOF when r ends inside a data block.
+func readBlock(r io.Reader, buf []byte) ([]byte, int, error) {
+	header := buf[:3]
+	n, err := io.ReadFull(r, header)
+	if err != nil {
+		return nil, n, err
+	}
+
+	length := int(header[1])<<8 + int(header[2])
+	if length < 3 {
+		return nil, n, ErrLenInvalid
+	}
+
+	m, err := io.ReadFull(r, buf[3:length])
+	n += m
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, n, err
+	}
+	return buf[:length], n, nil
+}
+
+// Offset returns the number of bytes read from the stream, it is the offset of the next data block.
+func (r *Reader) Offset() int64 {
+	return r.offset
+}
@@ -0,0 +1,139 @@
+package goasterix
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/mokhtarimokhtar/goasterix/util"
+)
+
+func TestReaderNext(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		input        string
+		reader       func(r io.Reader) io.Reader
+		nbOfRecords  []int
+		err          error // error expected at the end of stream
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "CAT048 + CAT034",
+			input: "300118fff7020836429b52a094c70181091302d06002b7490d0138a178cf422002e79a5d27a00c0060a3280030a4000040063a0743ce5b4020f5fff7020836429b54e000bc020901a2005c7802e800263946e50464b1cb6ca0029ea9491062a4546093880032d4000040059602f639590220f5fff7020836429b58a0909703ff026405a26002bb4066740815f6e795e002e56a0530ffdff860b0d80032fc00004003cf0810c9ef4020fdfff7020836429b56a0775d03700ec205786002be4060910815f9c363a002a49a0f30bfffff60c4600030a4000040057207674a004020fdfff7020836429b55a0468c029804b105786002c57101124d6070d3282002adfa3333a0140060c4600030a4000040026e07d75fc04020f5" +
+				"220014f6083602429b7110940028200094008000",
+			reader:      func(r io.Reader) io.Reader { return r },
+			nbOfRecords: []int{5, 1},
+			err:         io.EOF,
+		},
+		{
+			TestCaseName: "one byte reads",
+			input:        "220014f6083602429b7110940028200094008000 220014f6083602429b7110940028200094008000",
+			reader:       iotest.OneByteReader,
+			nbOfRecords:  []int{1, 1},
+			err:          io.EOF,
+		},
+		{
+			TestCaseName: "half reads",
+			input:        "220014f6083602429b7110940028200094008000 220014f6083602429b7110940028200094008000",
+			reader:       iotest.HalfReader,
+			nbOfRecords:  []int{1, 1},
+			err:          io.EOF,
+		},
+		{
+			TestCaseName: "stream ends inside a data block",
+			input:        "220014f6083602429b7110940028200094008000 220014f6083602429b71",
+			reader:       func(r io.Reader) io.Reader { return r },
+			nbOfRecords:  []int{1},
+			err:          io.ErrUnexpectedEOF,
+		},
+		{
+			TestCaseName: "stream ends inside a header",
+			input:        "220014f6083602429b7110940028200094008000 2200",
+			reader:       func(r io.Reader) io.Reader { return r },
+			nbOfRecords:  []int{1},
+			err:          io.ErrUnexpectedEOF,
+		},
+		{
+			TestCaseName: "invalid LEN",
+			input:        "220014f6083602429b7110940028200094008000 220002",
+			reader:       func(r io.Reader) io.Reader { return r },
+			nbOfRecords:  []int{1},
+			err:          ErrLenInvalid,
+		},
+		{
+			TestCaseName: "empty",
+			input:        "",
+			reader:       func(r io.Reader) io.Reader { return r },
+			nbOfRecords:  nil,
+			err:          io.EOF,
+		},
+	}
+
+	for _, row := range dataSet {
+		// Arrange
+		data, _ := util.HexStringToByte(row.input)
+		r := NewReader(row.reader(bytes.NewReader(data)))
+		var nbOfRecords []int
+		var err error
+
+		// Act
+		for {
+			var db *DataBlock
+			db, err = r.Next()
+			if err != nil {
+				break
+			}
+			nbOfRecords = append(nbOfRecords, len(db.Records))
+		}
+
+		// Assert
+		if err != row.err {
+			t.Errorf("FAIL: %s - error: %v; Expected: %v", row.TestCaseName, err, row.err)
+		} else {
+			t.Logf("SUCCESS: error: %v; Expected: %v", err, row.err)
+		}
+		if len(nbOfRecords) != len(row.nbOfRecords) {
+			t.Errorf("FAIL: %s - nbOfDataBlocks = %v; Expected: %v", row.TestCaseName, len(nbOfRecords), len(row.nbOfRecords))
+			continue
+		}
+		for i := range nbOfRecords {
+			if nbOfRecords[i] != row.nbOfRecords[i] {
+				t.Errorf("FAIL: %s - nbOfRecords = %v; Expected: %v", row.TestCaseName, nbOfRecords[i], row.nbOfRecords[i])
+			} else {
+				t.Logf("SUCCESS: nbOfRecords = %v; Expected: %v", nbOfRecords[i], row.nbOfRecords[i])
+			}
+		}
+	}
+}
+
+func TestReaderNext_DecodeError(t *testing.T) {
+	// Arrange
+	// the first data block has an unknown category, the Reader goes on with the next one
+	input := "00 0005 ffff 220014f6083602429b7110940028200094008000"
+	data, _ := util.HexStringToByte(input)
+	r := NewReader(bytes.NewReader(data))
+
+	// Act
+	_, err1 := r.Next()
+	offset := r.Offset()
+	db, err2 := r.Next()
+
+	// Assert
+	if err1 != ErrCategoryUnknown {
+		t.Errorf("FAIL: error: %v; Expected: %v", err1, ErrCategoryUnknown)
+	} else {
+		t.Logf("SUCCESS: error: %v; Expected: %v", err1, ErrCategoryUnknown)
+	}
+	if offset != 5 {
+		t.Errorf("FAIL: offset = %v; Expected: %v", offset, 5)
+	} else {
+		t.Logf("SUCCESS: offset = %v; Expected: %v", offset, 5)
+	}
+	if err2 != nil || db.Category != 34 {
+		t.Errorf("FAIL: error: %v, category = %v; Expected: %v, %v", err2, db.Category, nil, 34)
+	} else {
+		t.Logf("SUCCESS: error: %v, category = %v; Expected: %v, %v", err2, db.Category, nil, 34)
+	}
+}
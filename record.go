@@ -2,9 +2,9 @@ package goasterix
 
 import (
 	"bytes"
-	"encoding/binary"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"io"
 	"math/bits"
 
@@ -14,12 +14,63 @@ import (
 var (
 	// ErrDataFieldUnknown reports which ErrDatafield Unknown.
 	ErrDataFieldUnknown = errors.New("type of datafield not found")
+
+	// ErrConditionUnknown reports that no variant of a conditional UAP matches the discriminating item.
+	ErrConditionUnknown = errors.New("[ASTERIX] conditional UAP variant not found")
+
+	// ErrExpansionInvalid reports that the content of a RE or SP field does not match its sub-profile.
+	ErrExpansionInvalid = errors.New("[ASTERIX] RE/SP field does not match its sub-profile")
+
+	// ErrUAPInvalid reports that a data field of the User Application Profile can not be decoded (e.g. size 0).
+	ErrUAPInvalid = errors.New("[ASTERIX] invalid UAP definition")
 )
 
+// DecodeError reports the data field where the decoding of a record failed.
+// Offset is the position in byte of the data field (of the FSPEC if FRN is 0): from the start of the record
+// for Record.Decode, from the CAT field of the data block for DataBlock.Decode.
+// Record is the index of the record in the data block.
+type DecodeError struct {
+	Offset   int
+	Category uint8
+	Record   int
+	FRN      uint8
+	DataItem string
+	Err      error
+}
+
+func (e *DecodeError) Error() string {
+	field := "FSPEC"
+	if e.FRN != 0 {
+		field = fmt.Sprintf("FRN %d", e.FRN)
+	}
+	if e.DataItem != "" {
+		field += " " + e.DataItem
+	}
+	return fmt.Sprintf("[ASTERIX] CAT%03d record %d at offset %d, %s: %v", e.Category, e.Record, e.Offset, field, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
 type Record struct {
 	Cat   uint8
 	Fspec []byte
 	Items []Item
+
+	plan    *Plan      // UAP of the decoding or of the encoding, used by the editing (see Set)
+	block   *DataBlock // data block of the record, its LEN is updated by the editing
+	dropped int        // number of bytes of the items not selected by the decoding, see WithSelection
+
+	// storage of the items, reused by the decoding of a released record (see DataBlock.Release)
+	fspec      []byte // FSPEC of the items selected, see WithSelection
+	fixed      []Fixed
+	extended   []Extended
+	explicit   []Explicit
+	repetitive []Repetitive
+	compound   []Compound
+	sp         []SpecialPurpose
+	subItems   []Item
 }
 
 func NewRecord() *Record {
@@ -29,98 +80,129 @@ func NewRecord() *Record {
 // Decode extracts a Record of asterix data block (only one record).
 // An asterix data block can contain a or more records.
 // It returns the number of bytes unread and fills the Record Struct(Fspec, Items array) in byte.
+// The items do not alias data: data is copied once and the items are slices of this copy.
+// The Plan of stdUAP (see Compile) is cached, it is compiled again if the data fields of stdUAP are modified.
 func (rec *Record) Decode(data []byte, stdUAP uap.StandardUAP) (unRead int, err error) {
-	rec.Cat = stdUAP.Category
+	return rec.DecodePlan(data, profilePlan(stdUAP))
+}
 
-	rb := bytes.NewReader(data)
-	rec.Fspec, err = FspecReader(rb)
-	unRead = rb.Len()
-	if err != nil {
-		return unRead, err
-	}
+// DecodePlan extracts a Record like Decode with the compiled User Application Profile p.
+func (rec *Record) DecodePlan(data []byte, p *Plan) (unRead int, err error) {
+	tmp := make([]byte, len(data))
+	copy(tmp, data)
+	unRead, _, err = rec.decode(tmp, p, false)
+	return unRead, err
+}
 
-	frnIndex := FspecIndex(rec.Fspec)
-	offset := uint8(0) // offset shifts the index for a conditional UAP
+// decode extracts a Record like Decode, the items are slices of data.
+// In strict mode it returns the violations of the specification with their offset from the start of the record.
+func (rec *Record) decode(data []byte, p *Plan, strict bool) (unRead int, warnings []Warning, err error) {
+	rec.Cat = p.stdUAP.Category
+	rec.plan = p
+	rec.dropped = 0
 
-	for _, frn := range frnIndex {
-		uapItem := stdUAP.Items[frn-1-offset] // here the index corresponds to the FRN
+	c := cursor{data: data}
+	rec.Fspec, err = c.fspec()
+	if err != nil {
+		return c.len(), warnings, rec.decodeError(err, 0, uap.DataField{})
+	}
+	if kind, found := fspecWarning(rec.Fspec); strict && found {
+		warnings = append(warnings, Warning{Kind: kind, Category: rec.Cat})
+	}
 
-		item := NewItem(uapItem)
-		switch uapItem.Type {
-		case uap.Fixed:
-			tmp, err := FixedDataFieldReader(rb, uapItem.Fixed.Size)
-			if err != nil {
-				unRead = rb.Len()
-				return unRead, err
-			}
-			item.Fixed = &tmp
+	fields := p.items
+	for frn := nextFRN(rec.Fspec, 0); frn != 0; frn = nextFRN(rec.Fspec, frn) {
+		offset := c.pos
+		if int(frn) > len(fields) {
+			return c.len(), warnings, rec.decodeError(ErrFRNUnknown, offset, uap.DataField{FRN: frn})
+		}
+		pf := &fields[frn-1] // here the index corresponds to the FRN
 
-		case uap.Extended:
-			tmp, err := ExtendedDataFieldReader(rb, uapItem.Extended.PrimarySize, uapItem.Extended.SecondarySize)
-			if err != nil {
-				unRead = rb.Len()
-				return unRead, err
+		if pf.skip {
+			if err = skipItem(&c, pf, fields); err != nil {
+				return c.len(), warnings, rec.decodeError(err, offset, pf.field)
 			}
-			item.Extended = &tmp
-
-		case uap.Explicit:
-			tmp, err := ExplicitDataFieldReader(rb)
-			if err != nil {
-				unRead = rb.Len()
-				return unRead, err
+			rec.dropped += c.pos - offset
+		} else {
+			var item Item
+			if err = rec.readItem(&c, pf, fields, &item); err != nil {
+				return c.len(), warnings, rec.decodeError(err, offset, pf.field)
 			}
-			item.Explicit = &tmp
-
-		case uap.Repetitive:
-			tmp, err := RepetitiveDataFieldReader(rb, uapItem.Repetitive.SubItemSize)
-			if err != nil {
-				unRead = rb.Len()
-				return unRead, err
+			rec.Items = append(rec.Items, item)
+
+			if strict {
+				for _, kind := range itemWarnings(&item, pf.field, fields) {
+					warnings = append(warnings, Warning{
+						Kind:     kind,
+						Offset:   offset,
+						Category: rec.Cat,
+						FRN:      pf.field.FRN,
+						DataItem: pf.field.DataItem,
+					})
+				}
 			}
-			item.Repetitive = &tmp
+		}
 
-		case uap.Compound:
-			tmp, err := CompoundDataFieldReader(rb, uapItem.Compound)
-			if err != nil {
-				unRead = rb.Len()
-				return unRead, err
+		if cond := p.stdUAP.Condition; cond != nil && frn == cond.FRN {
+			var found bool
+			fields, found = p.variant(c.data[offset:c.pos])
+			if !found {
+				return c.len(), warnings, rec.decodeError(ErrConditionUnknown, offset, pf.field)
 			}
-			item.Compound = &tmp
+		}
+	}
+	if rec.dropped != 0 {
+		// the FSPEC of the items decoded, a record without selected item has an empty FSPEC octet
+		rec.dropped += len(rec.Fspec)
+		rec.fspec = rec.fspec[:0]
+		for i := range rec.Items {
+			rec.fspec = appendFRN(rec.fspec, rec.Items[i].Meta.FRN)
+		}
+		if len(rec.fspec) == 0 {
+			rec.fspec = append(rec.fspec, 0)
+		}
+		rec.Fspec = rec.fspec
+		rec.dropped -= len(rec.Fspec)
+	}
+	return c.len(), warnings, nil
+}
 
-		case uap.SP, uap.RE:
-			tmp, err := SPAndREDataFieldReader(rb)
-			if err != nil {
-				unRead = rb.Len()
-				return unRead, err
-			}
-			item.SP = &tmp
+// decodeError returns err located on the data field starting at offset of the record.
+func (rec *Record) decodeError(err error, offset int, field uap.DataField) *DecodeError {
+	return &DecodeError{
+		Offset:   offset,
+		Category: rec.Cat,
+		FRN:      field.FRN,
+		DataItem: field.DataItem,
+		Err:      err,
+	}
+}
 
-		case uap.RFS:
-			tmp, err := RFSDataFieldReader(rb, stdUAP.Items)
-			if err != nil {
-				unRead = rb.Len()
-				return unRead, err
-			}
-			item.RFS = &tmp
+// expansionField returns the definition of a RE or SP field with the sub-profile of the UAP, if any.
+func expansionField(field uap.DataField, stdUAP uap.StandardUAP) uap.DataField {
+	if len(field.Compound) != 0 {
+		return field
+	}
+	switch field.Type {
+	case uap.RE:
+		field.Compound = stdUAP.ReservedExpansion
+	case uap.SP:
+		field.Compound = stdUAP.SpecialPurpose
+	}
+	return field
+}
 
-		default:
-			err = ErrDataFieldUnknown
-			return unRead, err
-		}
-		unRead = rb.Len()
-		rec.Items = append(rec.Items, *item)
-
-		if uapItem.Conditional {
-			switch item.Meta.Type {
-			case uap.Fixed:
-				stdUAP.Items = selectUAPConditional(stdUAP.Category, item.Fixed.Data)
-			case uap.Extended:
-				stdUAP.Items = selectUAPConditional(stdUAP.Category, item.Extended.Primary)
-			}
-			offset = frn
-		}
+// conditionalFields returns the items of a conditional UAP selected by the discriminating item:
+// the items of the UAP until the discriminating item followed by the selected variant.
+func conditionalFields(stdUAP uap.StandardUAP, item *Item) ([]uap.DataField, error) {
+	variant, found := stdUAP.Condition.Select(item.Payload())
+	if !found || int(stdUAP.Condition.FRN) > len(stdUAP.Items) {
+		return nil, ErrConditionUnknown
 	}
-	return unRead, nil
+	fields := make([]uap.DataField, 0, int(stdUAP.Condition.FRN)+len(variant))
+	fields = append(fields, stdUAP.Items[:stdUAP.Condition.FRN]...)
+	fields = append(fields, variant...)
+	return fields, nil
 }
 
 // String returns a string(hex) representation of one asterix record (only existing items).
@@ -146,39 +228,18 @@ func (rec Record) Payload() []byte {
 	return pd
 }
 
-func selectUAPConditional(category uint8, field []byte) []uap.DataField {
-	var selectedUAP []uap.DataField
-	switch category {
-	case 1:
-		tmp := field[0] & 0x80 >> 7
-		if tmp == 1 {
-			selectedUAP = uap.Cat001TrackV12
-		} else {
-			selectedUAP = uap.Cat001PlotV12
-		}
-	case 26:
-		tmp := field[0] & 0x80 >> 7
-		if tmp == 1 {
-			selectedUAP = uap.Cat4TestTrack
-		} else {
-			selectedUAP = uap.Cat4TestPlot
-		}
-	}
-	return selectedUAP
-}
-
 // FspecReader returns a slice of FSPEC data record asterix.
 func FspecReader(reader io.Reader) ([]byte, error) {
 	var fspec []byte
 	var err error
+	var tmp [1]byte
 	for {
-		var tmp uint8
-		err = binary.Read(reader, binary.BigEndian, &tmp)
+		_, err = io.ReadFull(reader, tmp[:])
 		if err != nil {
 			return nil, err
 		}
-		fspec = append(fspec, tmp)
-		if tmp&0x01 == 0 {
+		fspec = append(fspec, tmp[0])
+		if tmp[0]&0x01 == 0 {
 			break
 		}
 	}
@@ -188,11 +249,15 @@ func FspecReader(reader io.Reader) ([]byte, error) {
 // FspecIndex returns an array of uint8 corresponding to number FRN(Field Reference Number of Items).
 // In other words, it transposes a fspec bits to an array FRNs.
 // e.g. fspec = 1010 1010 => frnIndex = []uint8{1, 3, 5, 7}
+// The bits beyond the FRN 255 are ignored.
 func FspecIndex(fspec []byte) []uint8 {
 	var frnIndex []uint8
 	for j, val := range fspec {
 		for i := 0; i < 7; i++ {
 			frn := 7*j + i + 1
+			if frn > 0xff {
+				return frnIndex
+			}
 			tmp := bits.RotateLeft8(val, i)
 			if tmp&0x80 != 0 {
 				frnIndex = append(frnIndex, uint8(frn))
@@ -202,19 +267,40 @@ func FspecIndex(fspec []byte) []uint8 {
 	return frnIndex
 }
 
+// DataFieldReader extracts one data field of any type according to its UAP definition.
+// The fields of a RFS data field are read with their definition in fields (see rfsField).
+// Like the other field readers, it decodes the field with the reader of Record.Decode on a copy of the bytes unread
+// of rb, then moves rb after the field.
+func DataFieldReader(rb *bytes.Reader, uapItem uap.DataField, fields []uap.DataField) (*Item, error) {
+	c := readerCursor(rb)
+	defer readerDone(rb, c)
+
+	pf := compileFields([]uap.DataField{uapItem}, uap.StandardUAP{}, nil)[0]
+	item := &Item{}
+	err := new(Record).readItem(c, &pf, compileFields(fields, uap.StandardUAP{}, nil), item)
+	return item, err
+}
+
+// readerCursor returns a cursor on a copy of the bytes unread of rb, the field readers do not alias the data of rb.
+func readerCursor(rb *bytes.Reader) *cursor {
+	data := make([]byte, rb.Len())
+	_, _ = rb.ReadAt(data, rb.Size()-int64(rb.Len()))
+	return &cursor{data: data}
+}
+
+// readerDone moves rb after the bytes read by the cursor c of readerCursor.
+func readerDone(rb *bytes.Reader, c *cursor) {
+	_, _ = rb.Seek(int64(c.pos), io.SeekCurrent)
+}
+
 // FixedDataFieldReader extracts a number(nb) of bytes(size) and returns a slice of bytes(data of item).
 // Fixed length Data Fields shall comprise a fixed number of octets.
 func FixedDataFieldReader(rb *bytes.Reader, size uint8) (Fixed, error) {
-	var err error
-	item := Fixed{}
+	c := readerCursor(rb)
+	defer readerDone(rb, c)
 
-	tmp := make([]byte, size)
-	err = binary.Read(rb, binary.BigEndian, &tmp)
-	if err != nil {
-		return item, err
-	}
-	item.Data = tmp
-	return item, err
+	data, err := c.next(int(size))
+	return Fixed{Data: data}, err
 }
 
 // ExtendedDataFieldReader extracts data item type Extended (FX: last bit = 1).
@@ -226,50 +312,22 @@ func FixedDataFieldReader(rb *bytes.Reader, size uint8) (Fixed, error) {
 // Least Significant Bit (LSB) of the last octet of the preceding part (either the primary part or a secondary part).
 // This bit which is reserved for that purpose is called the Field Extension Indicator (FX).
 func ExtendedDataFieldReader(rb *bytes.Reader, primarySize uint8, secondarySize uint8) (Extended, error) {
-	var err error
-	item := Extended{}
+	c := readerCursor(rb)
+	defer readerDone(rb, c)
 
-	tmp := make([]byte, primarySize)
-	err = binary.Read(rb, binary.BigEndian, &tmp)
-	if err != nil {
-		return item, err
-	}
-	item.Primary = tmp
-
-	if tmp[primarySize-1]&0x01 != 0 {
-		for {
-			tmp := make([]byte, secondarySize)
-			err = binary.Read(rb, binary.BigEndian, &tmp)
-			if err != nil {
-				return item, err
-			}
-			item.Secondary = append(item.Secondary, tmp...)
-			if tmp[secondarySize-1]&0x01 == 0 {
-				break
-			}
-		}
-	}
-	return item, err
+	return readExtended(c, int(primarySize), int(secondarySize))
 }
 
 // ExplicitDataFieldReader extracts a number of bytes define by the first byte.
 // Explicit length Data Fields shall start with a one-octet length indicator giving
 // the total field length in octets including the length indicator itself.
 func ExplicitDataFieldReader(rb *bytes.Reader) (Explicit, error) {
-	var err error
-	item := Explicit{}
-
-	err = binary.Read(rb, binary.BigEndian, &item.Len)
-	if err != nil {
-		return item, err
-	}
+	c := readerCursor(rb)
+	defer readerDone(rb, c)
 
-	tmp := make([]byte, item.Len-1)
-	err = binary.Read(rb, binary.BigEndian, &tmp)
-	if err != nil {
-		return item, err
-	}
-	item.Data = tmp
+	item := Explicit{}
+	var err error
+	item.Len, item.Data, err = readLenData(c)
 	return item, err
 }
 
@@ -278,20 +336,16 @@ func ExplicitDataFieldReader(rb *bytes.Reader) (Explicit, error) {
 // Repetitive Data Fields, being of a variable length, shall comprise a one-octet Field Repetition Indicator (REP)
 // signalling the presence of N consecutive sub-fields each of the same pre-determined length.
 func RepetitiveDataFieldReader(rb *bytes.Reader, SubItemSize uint8) (Repetitive, error) {
-	var err error
-	item := Repetitive{}
+	c := readerCursor(rb)
+	defer readerDone(rb, c)
 
-	err = binary.Read(rb, binary.BigEndian, &item.Rep)
-	if err != nil {
-		return item, err
-	}
-
-	tmp := make([]byte, item.Rep*SubItemSize)
-	err = binary.Read(rb, binary.BigEndian, &tmp)
+	item := Repetitive{}
+	var err error
+	item.Rep, err = c.byte()
 	if err != nil {
 		return item, err
 	}
-	item.Data = tmp
+	item.Data, err = c.next(int(item.Rep) * int(SubItemSize))
 	return item, err
 }
 
@@ -302,57 +356,14 @@ func RepetitiveDataFieldReader(rb *bytes.Reader, SubItemSize uint8) (Repetitive,
 // The definition, structure and format of the data subfields are part of the description of the relevant Compound Data
 // Item. Data subfields shall be either fixed length, extended length, explicit length or repetitive, but not compound.
 func CompoundDataFieldReader(rb *bytes.Reader, cp []uap.DataField) (Compound, error) {
-	var err error
-	items := Compound{}
+	c := readerCursor(rb)
+	defer readerDone(rb, c)
 
-	items.Primary, err = FspecReader(rb)
-	if err != nil {
-		return items, err
+	tmp, err := new(Record).readCompound(c, compileFields(cp, uap.StandardUAP{}, nil))
+	if tmp == nil {
+		return Compound{}, err
 	}
-	frnIndex := FspecIndex(items.Primary)
-
-	for _, frn := range frnIndex {
-		uapItem := cp[frn-1]
-		item := NewItem(uapItem)
-		switch uapItem.Type {
-		case uap.Fixed:
-			tmp, err := FixedDataFieldReader(rb, uapItem.Fixed.Size)
-			if err != nil {
-				return items, err
-			}
-			item.Fixed = &tmp
-			items.Secondary = append(items.Secondary, *item)
-
-		case uap.Extended:
-			tmp, err := ExtendedDataFieldReader(rb, uapItem.Extended.PrimarySize, uapItem.Extended.SecondarySize)
-			if err != nil {
-				return items, err
-			}
-			item.Extended = &tmp
-			items.Secondary = append(items.Secondary, *item)
-
-		case uap.Explicit:
-			tmp, err := ExplicitDataFieldReader(rb)
-			if err != nil {
-				return items, err
-			}
-			item.Explicit = &tmp
-			items.Secondary = append(items.Secondary, *item)
-
-		case uap.Repetitive:
-			tmp, err := RepetitiveDataFieldReader(rb, uapItem.Repetitive.SubItemSize)
-			if err != nil {
-				return items, err
-			}
-			item.Repetitive = &tmp
-			items.Secondary = append(items.Secondary, *item)
-
-		default:
-			err = ErrDataFieldUnknown
-			return items, err
-		}
-	}
-	return items, err
+	return *tmp, err
 }
 
 // RFSDataFieldReader
@@ -362,45 +373,29 @@ func CompoundDataFieldReader(rb *bytes.Reader, cp []uap.DataField) (Compound, er
 // - the first octet provides the number, N, of Data Fields following;
 // - N fields in any arbitrary order each consisting of a one-octet FRN immediately followed by the contents of the
 // Data Item associated with the preceding FRN.
+// items is the list of items of the record, a FRN absent of items returns ErrFRNUnknown.
+// A Data Field of the sequence can be of any type except RFS.
 func RFSDataFieldReader(rb *bytes.Reader, items []uap.DataField) (RandomFieldSequencing, error) {
-	var err error
-	rfs := RandomFieldSequencing{}
-	// N is the total number of datafields
-	err = binary.Read(rb, binary.BigEndian, &rfs.N)
-	if err != nil {
-		return rfs, err
-	}
-	for i := uint8(0); i < rfs.N; i++ {
-		// retrieve random FRN
-		var frn uint8
-		err := binary.Read(rb, binary.BigEndian, &frn)
-		if err != nil {
-			return rfs, err
-		}
-
-		for _, field := range items {
-			if frn == field.FRN {
-				rf := new(RandomField)
-				rf.FRN = frn
+	c := readerCursor(rb)
+	defer readerDone(rb, c)
 
-				// todo: work just for Fixed datafield use case
-				tmp := make([]byte, field.Fixed.Size)
+	tmp, err := new(Record).readRFS(c, compileFields(items, uap.StandardUAP{}, nil))
+	if tmp == nil {
+		return RandomFieldSequencing{}, err
+	}
+	return *tmp, err
+}
 
-				err := binary.Read(rb, binary.BigEndian, &tmp)
-				if err != nil {
-					return rfs, err
-				}
-				item := NewItem(field)
-				fixed := Fixed{}
-				fixed.Data = tmp
-				item.Fixed = &fixed
-				rf.Field = *item
-				rfs.Sequence = append(rfs.Sequence, *rf)
-			}
+// rfsField returns the UAP definition of a FRN.
+// The FRNs of a RFS data field refer to the items of the record, not to subfields: items is the list of items
+// of the record (the variant of a conditional UAP).
+func rfsField(frn uint8, items []uap.DataField) (uap.DataField, bool) {
+	for _, field := range items {
+		if frn == field.FRN {
+			return field, true
 		}
 	}
-
-	return rfs, err
+	return uap.DataField{}, false
 }
 
 // SPAndREDataFieldReader extracts returns a slice
@@ -409,20 +404,11 @@ func RFSDataFieldReader(rb *bytes.Reader, items []uap.DataField) (RandomFieldSeq
 // Reserved Expansion Data
 // Field Special Purpose field
 func SPAndREDataFieldReader(rb *bytes.Reader) (SpecialPurpose, error) {
-	var err error
-	sp := SpecialPurpose{}
-
-	err = binary.Read(rb, binary.BigEndian, &sp.Len)
-	if err != nil {
-		return sp, err
-	}
-
-	tmp := make([]byte, sp.Len-1)
-	err = binary.Read(rb, binary.BigEndian, &tmp)
-	if err != nil {
-		return sp, err
-	}
-	sp.Data = tmp
+	c := readerCursor(rb)
+	defer readerDone(rb, c)
 
+	sp := SpecialPurpose{}
+	var err error
+	sp.Len, sp.Data, err = readLenData(c)
 	return sp, err
 }
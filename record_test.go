@@ -2,6 +2,7 @@ package goasterix
 
 import (
 	"bytes"
+	"errors"
 	"github.com/mokhtarimokhtar/goasterix/util"
 	"io"
 	"reflect"
@@ -79,7 +80,7 @@ func TestFspecReader_Invalid(t *testing.T) {
 	fspec, err := FspecReader(rb)
 
 	// Assert
-	if err != io.EOF {
+	if !errors.Is(err, io.EOF) {
 		t.Errorf("FAIL: error: %s; Expected: %v", err, io.EOF)
 	} else {
 		t.Logf("SUCCESS: error: %v; Expected: %v", err, io.EOF)
@@ -164,7 +165,7 @@ func TestFixedDataFieldReader_Invalid(t *testing.T) {
 	item, err := FixedDataFieldReader(rb, nb)
 
 	// Assert
-	if err != io.ErrUnexpectedEOF {
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
 		t.Errorf("FAIL: error: %v; Expected: %v", err, io.ErrUnexpectedEOF)
 	} else {
 		t.Logf("SUCCESS: error: %v; Expected: %v", err, io.ErrUnexpectedEOF)
@@ -272,7 +273,7 @@ func TestExtendedDataFieldReader(t *testing.T) {
 		item, err := ExtendedDataFieldReader(rb, row.primarySize, row.secondarySize)
 
 		// Assert
-		if err != row.err {
+		if !errors.Is(err, row.err) {
 			t.Errorf("FAIL: %s - error: %v; Expected: %v", row.TestCaseName, err, row.err)
 		} else {
 			t.Logf("SUCCESS: error: %v; Expected: %v", err, row.err)
@@ -332,7 +333,7 @@ func TestExplicitDataFieldReader(t *testing.T) {
 		item, err := ExplicitDataFieldReader(rb)
 
 		// Assert
-		if err != row.err {
+		if !errors.Is(err, row.err) {
 			t.Errorf("FAIL: %s - error: %v; Expected: %v", row.TestCaseName, err, row.err)
 		} else {
 			t.Logf("SUCCESS: error: %v; Expected: %v", err, row.err)
@@ -397,7 +398,7 @@ func TestRepetitiveDataFieldReader(t *testing.T) {
 		item, err := RepetitiveDataFieldReader(rb, row.SubItemSize)
 
 		// Assert
-		if err != row.err {
+		if !errors.Is(err, row.err) {
 			t.Errorf("FAIL: %s - error: %v; Expected: %v", row.TestCaseName, err, row.err)
 		} else {
 			t.Logf("SUCCESS: error: %v; Expected: %v", err, row.err)
@@ -574,7 +575,7 @@ func TestCompoundDataFieldReader(t *testing.T) {
 		cp, err := CompoundDataFieldReader(rb, row.item)
 
 		// Assert
-		if err != row.err {
+		if !errors.Is(err, row.err) {
 			t.Errorf("FAIL: error: %v; Expected: %v", err, row.err)
 		} else {
 			t.Logf("SUCCESS: error: %v; Expected: %v", err, row.err)
@@ -635,7 +636,7 @@ func TestSPAndREDataFieldReader(t *testing.T) {
 		cp, err := SPAndREDataFieldReader(rb)
 
 		// Assert
-		if err != row.err {
+		if !errors.Is(err, row.err) {
 			t.Errorf("FAIL: error: %v; Expected: %v", err, row.err)
 		} else {
 			t.Logf("SUCCESS: error: %v; Expected: %v", err, row.err)
@@ -733,6 +734,96 @@ func TestRFSDataFieldReader(t *testing.T) {
 			output:       RandomFieldSequencing{},
 			err:          io.EOF,
 		},
+		{
+			TestCaseName: "testcase 5: extended, explicit, repetitive and compound",
+			input:        "04 02 fffffe 03 03ffff 04 02ffffffff 05 80ff",
+			item:         uap.Cat4Test.Items,
+			output: RandomFieldSequencing{
+				N: 0x04,
+				Sequence: []RandomField{
+					{
+						FRN: 0x02,
+						Field: Item{
+							Meta: MetaItem{
+								FRN:         2,
+								DataItem:    "I026/002",
+								Description: "Extended type field for test",
+								Type:        uap.Extended,
+							},
+							Extended: &Extended{Primary: []byte{0xff}, Secondary: []byte{0xff, 0xfe}},
+						},
+					},
+					{
+						FRN: 0x03,
+						Field: Item{
+							Meta: MetaItem{
+								FRN:         3,
+								DataItem:    "I026/003",
+								Description: "Explicit type field for test",
+								Type:        uap.Explicit,
+							},
+							Explicit: &Explicit{Len: 0x03, Data: []byte{0xff, 0xff}},
+						},
+					},
+					{
+						FRN: 0x04,
+						Field: Item{
+							Meta: MetaItem{
+								FRN:         4,
+								DataItem:    "I026/004",
+								Description: "Repetitive type field for test",
+								Type:        uap.Repetitive,
+							},
+							Repetitive: &Repetitive{Rep: 0x02, Data: []byte{0xff, 0xff, 0xff, 0xff}},
+						},
+					},
+					{
+						FRN: 0x05,
+						Field: Item{
+							Meta: MetaItem{
+								FRN:         5,
+								DataItem:    "I026/005",
+								Description: "Compound type field for test",
+								Type:        uap.Compound,
+							},
+							Compound: &Compound{
+								Primary: []byte{0x80},
+								Secondary: []Item{
+									{
+										Meta: MetaItem{
+											FRN:         1,
+											DataItem:    "Compound/001",
+											Description: "Compound Fixed type field for test",
+											Type:        uap.Fixed,
+										},
+										Fixed: &Fixed{Data: []byte{0xff}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			err: nil,
+		},
+		{
+			TestCaseName: "testcase 6: unknown FRN",
+			input:        "01 1e ff",
+			item:         uap.Cat4Test.Items,
+			output: RandomFieldSequencing{
+				N: 0x01,
+			},
+			err: ErrFRNUnknown,
+		},
+		{
+			TestCaseName: "testcase 7: nested RFS",
+			input:        "01 06 01 01 ffff",
+			item:         uap.Cat4Test.Items,
+			output: RandomFieldSequencing{
+				N: 0x01,
+			},
+			err: ErrDataFieldUnknown,
+		},
 	}
 
 	for _, row := range dataSet {
@@ -744,7 +835,7 @@ func TestRFSDataFieldReader(t *testing.T) {
 		cp, err := RFSDataFieldReader(rb, row.item)
 
 		// Assert
-		if err != row.err {
+		if !errors.Is(err, row.err) {
 			t.Errorf("FAIL: error: %v; Expected: %v", err, row.err)
 		} else {
 			t.Logf("SUCCESS: error: %v; Expected: %v", err, row.err)
@@ -827,7 +918,7 @@ func TestRecordDecode_NbOfItems(t *testing.T) {
 		unRead, err := rec.Decode(data, row.uap)
 
 		// Assert
-		if err != row.err {
+		if !errors.Is(err, row.err) {
 			t.Errorf("FAIL: error: %s; Expected: %v", err, row.err)
 		} else {
 			t.Logf("SUCCESS: error: %v; Expected: %v", err, row.err)
@@ -857,7 +948,7 @@ func TestRecordDecode_Empty(t *testing.T) {
 	unRead, err := rec.Decode(data, uap048)
 
 	// Assert
-	if err != io.EOF {
+	if !errors.Is(err, io.EOF) {
 		t.Errorf("FAIL: error = %v; Expected: %v", err, io.EOF)
 	} else {
 		t.Logf("SUCCESS: error: %v; Expected: %v", err, io.EOF)
@@ -1174,6 +1265,338 @@ func TestRecordDecode_Cat4TestPlotFullRecord(t *testing.T) {
 	}
 }
 
+func TestRecordDecode_Condition(t *testing.T) {
+	// Setup
+	// a user-supplied profile where the message type (second octet of FRN 1) selects the item of FRN 2
+	msgProfile := uap.StandardUAP{
+		Category: 26,
+		Items: []uap.DataField{
+			{
+				FRN:      1,
+				DataItem: "I026/000",
+				Type:     uap.Fixed,
+				Fixed:    uap.FixedField{Size: 2},
+			},
+		},
+		Condition: &uap.Condition{
+			FRN:   1,
+			Octet: 1,
+			Mask:  0x0f,
+			Variants: map[uint8][]uap.DataField{
+				0x01: {{FRN: 2, DataItem: "I026/001", Type: uap.Fixed, Fixed: uap.FixedField{Size: 1}}},
+				0x02: {{FRN: 2, DataItem: "I026/002", Type: uap.Fixed, Fixed: uap.FixedField{Size: 3}}},
+				0x03: {{FRN: 2, DataItem: "I026/003", Type: uap.Explicit}},
+			},
+		},
+	}
+	type dataTest struct {
+		TestCase string
+		input    string
+		dataItem string
+		unRead   int
+		err      error
+	}
+	dataSet := []dataTest{
+		{
+			TestCase: "message type 1",
+			input:    "c0 ff01 aa",
+			dataItem: "I026/001",
+			unRead:   0,
+			err:      nil,
+		},
+		{
+			TestCase: "message type 2",
+			input:    "c0 fff2 aabbcc",
+			dataItem: "I026/002",
+			unRead:   0,
+			err:      nil,
+		},
+		{
+			TestCase: "message type 3",
+			input:    "c0 ff03 03aabb",
+			dataItem: "I026/003",
+			unRead:   0,
+			err:      nil,
+		},
+		{
+			TestCase: "message type unknown",
+			input:    "c0 ff04 aa",
+			dataItem: "",
+			unRead:   1,
+			err:      ErrConditionUnknown,
+		},
+	}
+
+	for _, row := range dataSet {
+		// Arrange
+		data, _ := util.HexStringToByte(row.input)
+		rec := NewRecord()
+
+		// Act
+		unRead, err := rec.Decode(data, msgProfile)
+
+		// Assert
+		if !errors.Is(err, row.err) {
+			t.Errorf("FAIL: %s - error = %v; Expected: %v", row.TestCase, err, row.err)
+		} else {
+			t.Logf("SUCCESS: error: %v; Expected: %v", err, row.err)
+		}
+		if unRead != row.unRead {
+			t.Errorf("FAIL: %s - unRead = %v; Expected: %v", row.TestCase, unRead, row.unRead)
+		} else {
+			t.Logf("SUCCESS: unRead = %v; Expected: %v", unRead, row.unRead)
+		}
+		if row.err != nil {
+			continue
+		}
+		if len(rec.Items) != 2 || rec.Items[1].Meta.DataItem != row.dataItem {
+			t.Errorf("FAIL: %s - items = %v; Expected: %v", row.TestCase, rec.String(), row.dataItem)
+		} else {
+			t.Logf("SUCCESS: items = %v; Expected: %v", rec.String(), row.dataItem)
+		}
+	}
+}
+
+func TestRecordDecode_ConditionalDeprecated(t *testing.T) {
+	// Setup
+	// Cat001V12 declared like the profiles written before uap.Condition: the discriminating item is Conditional
+	legacy := uap.Cat001V12
+	legacy.Condition = nil
+	legacy.Items = append([]uap.DataField{}, uap.Cat001V12.Items...)
+	legacy.Items[1].Conditional = true
+	type dataTest struct {
+		TestCase string
+		input    string
+	}
+	dataSet := []dataTest{
+		{
+			TestCase: "plot",
+			input:    "f0 0831 00 0a8abb2e 3802",
+		},
+		{
+			TestCase: "track",
+			input:    "f502 0831 98 01bf 0a1ebb43 022538e2 00",
+		},
+	}
+
+	for _, row := range dataSet {
+		// Arrange
+		data, _ := util.HexStringToByte(row.input)
+		expected := NewRecord()
+		_, _ = expected.Decode(data, uap.Cat001V12)
+		rec := NewRecord()
+
+		// Act
+		unRead, err := rec.Decode(data, legacy)
+
+		// Assert
+		if err != nil || unRead != 0 {
+			t.Errorf("FAIL: %s - error = %v, unRead = %v; Expected: %v, 0", row.TestCase, err, unRead, nil)
+		} else {
+			t.Logf("SUCCESS: %s - error: %v; Expected: %v", row.TestCase, err, nil)
+		}
+		if !reflect.DeepEqual(rec.String(), expected.String()) {
+			t.Errorf("FAIL: %s - items = %v; Expected: %v", row.TestCase, rec.String(), expected.String())
+		} else {
+			t.Logf("SUCCESS: %s - items = %v; Expected: %v", row.TestCase, rec.String(), expected.String())
+		}
+	}
+}
+
+func TestRecordDecode_ConditionalDeprecatedUnknown(t *testing.T) {
+	// Arrange
+	// Cat4Test declared as CAT027 with Conditional: no profile of CAT027 in uap.DefaultProfiles gives its variants
+	legacy := uap.Cat4Test
+	legacy.Category = 27
+	legacy.Condition = nil
+	legacy.Items = append([]uap.DataField{}, uap.Cat4Test.Items...)
+	legacy.Items[9].Conditional = true
+	data, _ := util.HexStringToByte("0120 80")
+	rec := NewRecord()
+
+	// Act
+	_, err := rec.Decode(data, legacy)
+
+	// Assert
+	if !errors.Is(err, ErrConditionUnknown) {
+		t.Errorf("FAIL: error = %v; Expected: %v", err, ErrConditionUnknown)
+	} else {
+		t.Logf("SUCCESS: error = %v; Expected: %v", err, ErrConditionUnknown)
+	}
+}
+
+func TestRecordDecode_Expansion(t *testing.T) {
+	// Setup
+	// Cat4Test with the sub-profiles of RE (FRN 8) and SP (FRN 9)
+	subProfile := []uap.DataField{
+		{FRN: 1, DataItem: "Sub/001", Type: uap.Fixed, Fixed: uap.FixedField{Size: 1}},
+		{FRN: 2, DataItem: "Sub/002", Type: uap.Explicit},
+	}
+	stdUAP := uap.Cat4Test
+	stdUAP.ReservedExpansion = subProfile
+	stdUAP.SpecialPurpose = subProfile
+	type dataTest struct {
+		TestCase string
+		input    string
+		output   *Compound
+		unRead   int
+		err      error
+	}
+	dataSet := []dataTest{
+		{
+			TestCase: "RE with sub-profile",
+			input:    "0180 06 c0 aa 03bbcc",
+			output: &Compound{
+				Primary: []byte{0xc0},
+				Secondary: []Item{
+					{
+						Meta:  MetaItem{FRN: 1, DataItem: "Sub/001", Type: uap.Fixed},
+						Fixed: &Fixed{Data: []byte{0xaa}},
+					},
+					{
+						Meta:     MetaItem{FRN: 2, DataItem: "Sub/002", Type: uap.Explicit},
+						Explicit: &Explicit{Len: 0x03, Data: []byte{0xbb, 0xcc}},
+					},
+				},
+			},
+			unRead: 0,
+			err:    nil,
+		},
+		{
+			TestCase: "SP with sub-profile",
+			input:    "0140 03 80 aa",
+			output: &Compound{
+				Primary: []byte{0x80},
+				Secondary: []Item{
+					{
+						Meta:  MetaItem{FRN: 1, DataItem: "Sub/001", Type: uap.Fixed},
+						Fixed: &Fixed{Data: []byte{0xaa}},
+					},
+				},
+			},
+			unRead: 0,
+			err:    nil,
+		},
+		{
+			TestCase: "RE shorter than sub-profile",
+			input:    "0180 04 c0 aa 03",
+			output:   nil,
+			unRead:   0,
+			err:      ErrExpansionInvalid,
+		},
+		{
+			TestCase: "RE longer than sub-profile",
+			input:    "0180 05 80 aa bbcc",
+			output:   nil,
+			unRead:   0,
+			err:      ErrExpansionInvalid,
+		},
+	}
+
+	for _, row := range dataSet {
+		// Arrange
+		data, _ := util.HexStringToByte(row.input)
+		rec := NewRecord()
+
+		// Act
+		unRead, err := rec.Decode(data, stdUAP)
+
+		// Assert
+		if !errors.Is(err, row.err) {
+			t.Errorf("FAIL: %s - error = %v; Expected: %v", row.TestCase, err, row.err)
+		} else {
+			t.Logf("SUCCESS: error: %v; Expected: %v", err, row.err)
+		}
+		if unRead != row.unRead {
+			t.Errorf("FAIL: %s - unRead = %v; Expected: %v", row.TestCase, unRead, row.unRead)
+		} else {
+			t.Logf("SUCCESS: unRead = %v; Expected: %v", unRead, row.unRead)
+		}
+		if row.err != nil {
+			continue
+		}
+		if len(rec.Items) != 1 || rec.Items[0].SP == nil || !reflect.DeepEqual(rec.Items[0].SP.Compound, row.output) {
+			t.Errorf("FAIL: %s - items = %v; Expected: %v", row.TestCase, rec.Items, row.output)
+		} else {
+			t.Logf("SUCCESS: items = %v; Expected: %v", rec.Items[0].SP.Compound, row.output)
+		}
+	}
+}
+
+func TestRecordDecode_Malformed(t *testing.T) {
+	// Setup
+	stdUAP := uap.StandardUAP{
+		Category: 26,
+		Items: []uap.DataField{
+			{FRN: 1, DataItem: "I026/001", Type: uap.Explicit},
+			{FRN: 2, DataItem: "I026/002", Type: uap.SP},
+			{FRN: 3, DataItem: "I026/003", Type: uap.Repetitive, Repetitive: uap.RepetitiveField{SubItemSize: 2}},
+			{FRN: 4, DataItem: "I026/004", Type: uap.Extended},
+			{FRN: 5, DataItem: "I026/005", Type: uap.Compound, Compound: []uap.DataField{
+				{FRN: 1, DataItem: "Compound/001", Type: uap.Fixed, Fixed: uap.FixedField{Size: 1}},
+			}},
+		},
+	}
+	type dataTest struct {
+		TestCase string
+		input    string
+		err      error
+	}
+	dataSet := []dataTest{
+		{
+			TestCase: "FRN beyond UAP",
+			input:    "02 ff",
+			err:      ErrFRNUnknown,
+		},
+		{
+			TestCase: "FSPEC beyond 255 FRN",
+			input:    "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+			err:      io.EOF,
+		},
+		{
+			TestCase: "Explicit length 0",
+			input:    "80 00 ff",
+			err:      ErrLenInvalid,
+		},
+		{
+			TestCase: "SP length 0",
+			input:    "40 00 ff",
+			err:      ErrLenInvalid,
+		},
+		{
+			TestCase: "Repetitive larger than 255 bytes",
+			input:    "20 ff",
+			err:      io.EOF,
+		},
+		{
+			TestCase: "Extended of size 0",
+			input:    "10 ff",
+			err:      ErrUAPInvalid,
+		},
+		{
+			TestCase: "Compound subfield beyond UAP",
+			input:    "08 40 ff",
+			err:      ErrFRNUnknown,
+		},
+	}
+
+	for _, row := range dataSet {
+		// Arrange
+		data, _ := util.HexStringToByte(row.input)
+		rec := NewRecord()
+
+		// Act
+		_, err := rec.Decode(data, stdUAP)
+
+		// Assert
+		if !errors.Is(err, row.err) {
+			t.Errorf("FAIL: %s - error = %v; Expected: %v", row.TestCase, err, row.err)
+		} else {
+			t.Logf("SUCCESS: error: %v; Expected: %v", err, row.err)
+		}
+	}
+}
+
 func TestRecordDecode_Cat4TestError(t *testing.T) {
 	// Setup
 	type dataTest struct {
@@ -1235,7 +1658,7 @@ func TestRecordDecode_Cat4TestError(t *testing.T) {
 		remaining, err := rec.Decode(data, uap4Test)
 
 		// Assert
-		if err != row.err {
+		if !errors.Is(err, row.err) {
 			t.Errorf("FAIL: %s - error = %v; Expected: %v", row.TestCase, err, row.err)
 		} else {
 			t.Logf("SUCCESS: error: %v; Expected: %v", err, row.err)
@@ -0,0 +1,325 @@
+package goasterix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/mokhtarimokhtar/goasterix/uap"
+	"github.com/mokhtarimokhtar/goasterix/util"
+)
+
+// sampleFiles are the test files containing hexadecimal samples.
+var sampleFiles = []string{"*_test.go", "transform/*_test.go", "commbds/*_test.go", "commbds/*/*_test.go",
+	"generic/*_test.go", "diff/*_test.go"}
+
+// testSamples returns the hexadecimal samples of the test files (sampleFiles): the string literals which are
+// valid hexadecimal data.
+func testSamples(t *testing.T) [][]byte {
+	var files []string
+	for _, pattern := range sampleFiles {
+		tmp, err := filepath.Glob(pattern)
+		if err != nil {
+			t.Fatalf("FAIL: error = %v; Expected: %v", err, nil)
+		}
+		files = append(files, tmp...)
+	}
+	var samples [][]byte
+	fset := token.NewFileSet()
+	for _, file := range files {
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			t.Fatalf("FAIL: %s - error = %v; Expected: %v", file, err, nil)
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			lit, ok := n.(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return true
+			}
+			s, err := strconv.Unquote(lit.Value)
+			if err != nil {
+				return true
+			}
+			if data, err := util.HexStringToByte(s); err == nil && len(data) != 0 {
+				samples = append(samples, data)
+			}
+			return true
+		})
+	}
+	return samples
+}
+
+// pcapDatagrams returns the UDP payloads of a pcap file (Ethernet, IPv4).
+func pcapDatagrams(t *testing.T, name string) [][]byte {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatalf("FAIL: error = %v; Expected: %v", err, nil)
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	if data[0] == 0xa1 {
+		order = binary.BigEndian
+	}
+
+	var datagrams [][]byte
+	for offset := 24; offset+16 <= len(data); {
+		size := int(order.Uint32(data[offset+8:]))
+		offset += 16
+		if offset+size > len(data) {
+			break
+		}
+		frame := data[offset : offset+size]
+		offset += size
+
+		// Ethernet II + IPv4 + UDP
+		if len(frame) < 14+20+8 || frame[12] != 0x08 || frame[13] != 0x00 || frame[14+9] != 17 {
+			continue
+		}
+		ihl := int(frame[14]&0x0f) * 4
+		if len(frame) < 14+ihl+8 {
+			continue
+		}
+		udpLen := int(binary.BigEndian.Uint16(frame[14+ihl+4:]))
+		if udpLen < 8 || 14+ihl+udpLen > len(frame) {
+			continue
+		}
+		datagrams = append(datagrams, frame[14+ihl+8:14+ihl+udpLen])
+	}
+	return datagrams
+}
+
+// sameItems returns true if the items a and b have the same FRNs and contents. The data subfields of Compound items
+// (and of RE and SP items with sub-profile) are compared instead of their primary subfield, which is rebuilt
+// by the encoding.
+func sameItems(a, b []Item) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !sameItem(&a[i], &b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameItem(a, b *Item) bool {
+	if a.Meta.FRN != b.Meta.FRN {
+		return false
+	}
+	switch {
+	case a.Compound != nil && b.Compound != nil:
+		return sameItems(a.Compound.Secondary, b.Compound.Secondary)
+	case a.SP != nil && b.SP != nil && a.SP.Compound != nil && b.SP.Compound != nil:
+		return sameItems(a.SP.Compound.Secondary, b.SP.Compound.Secondary)
+	case a.RFS != nil && b.RFS != nil:
+		if len(a.RFS.Sequence) != len(b.RFS.Sequence) {
+			return false
+		}
+		for i := range a.RFS.Sequence {
+			ra, rb := &a.RFS.Sequence[i], &b.RFS.Sequence[i]
+			if ra.FRN != rb.FRN || !sameItem(&ra.Field, &rb.Field) {
+				return false
+			}
+		}
+		return true
+	}
+	return bytes.Equal(a.Payload(), b.Payload())
+}
+
+// encodeRecord encodes the items of rec with Record.Encode.
+func encodeRecord(rec *Record, stdUAP uap.StandardUAP) ([]byte, error) {
+	items := make(map[uint8]Item, len(rec.Items))
+	for _, item := range rec.Items {
+		items[item.Meta.FRN] = item
+	}
+	return NewRecord().Encode(items, stdUAP)
+}
+
+// canonicalItems returns true if fspec (FSPEC or primary subfield) and items can be the output of their encoding:
+// Encode rebuilds the FSPEC and the primary subfields from the FRNs of the items, without trailing zero octet
+// or bits beyond the FRN 255, and rejects the Compound items without data subfield and the RFS items without field.
+func canonicalItems(fspec []byte, items []Item) bool {
+	frnIndex := make([]uint8, 0, len(items))
+	for _, item := range items {
+		frnIndex = append(frnIndex, item.Meta.FRN)
+	}
+	if !bytes.Equal(fspec, FspecFromIndex(frnIndex)) {
+		return false
+	}
+	for i := range items {
+		item := &items[i]
+		switch {
+		case item.Compound != nil && !canonicalItems(item.Compound.Primary, item.Compound.Secondary):
+			return false
+		case item.SP != nil && item.SP.Compound != nil && !canonicalItems(item.SP.Compound.Primary, item.SP.Compound.Secondary):
+			return false
+		case item.RFS != nil:
+			if len(item.RFS.Sequence) == 0 {
+				return false
+			}
+			for _, rf := range item.RFS.Sequence {
+				if rf.Field.Compound != nil && !canonicalItems(rf.Field.Compound.Primary, rf.Field.Compound.Secondary) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// minRecords and minBlocks are the minimum numbers of records and data blocks decoded from the samples:
+// a sample which does not decode anymore fails the round-trip tests.
+const (
+	minRecords = 1300
+	minBlocks  = 8000
+)
+
+// TestRoundTrip_Records checks that every record decoded from the samples of the tests, with each profile,
+// is encoded again (Record.Payload) to the bytes decoded, and encoded (Record.Encode) to the bytes decoded and
+// decoded again with the same items.
+// A sample which is not canonical (see canonicalItems) can be rejected by the encoding or encoded to other bytes.
+func TestRoundTrip_Records(t *testing.T) {
+	// Arrange
+	profiles := []uap.StandardUAP{uap.Cat4Test}
+	for _, stdUAP := range uap.DefaultProfiles {
+		profiles = append(profiles, stdUAP)
+	}
+	expansion := uap.Cat4Test
+	expansion.ReservedExpansion = []uap.DataField{
+		{FRN: 1, DataItem: "Sub/001", Type: uap.Fixed, Fixed: uap.FixedField{Size: 1}},
+		{FRN: 2, DataItem: "Sub/002", Type: uap.Explicit},
+	}
+	expansion.SpecialPurpose = expansion.ReservedExpansion
+	profiles = append(profiles, expansion)
+	nbOfRecords := 0
+
+	for _, sample := range testSamples(t) {
+		for _, stdUAP := range profiles {
+			rec := NewRecord()
+			unRead, err := rec.Decode(sample, stdUAP)
+			if err != nil || len(rec.Items) == 0 {
+				continue
+			}
+			nbOfRecords++
+			expected := sample[:len(sample)-unRead]
+
+			// Act
+			payload := rec.Payload()
+			data, err := encodeRecord(rec, stdUAP)
+
+			// Assert
+			if !bytes.Equal(payload, expected) {
+				t.Errorf("FAIL: CAT%03d payload = %x; Expected: %x", stdUAP.Category, payload, expected)
+			}
+			isCanonical := canonicalItems(rec.Fspec, rec.Items)
+			if err != nil {
+				if isCanonical {
+					t.Errorf("FAIL: CAT%03d %x - error = %v; Expected: %v", stdUAP.Category, expected, err, nil)
+				}
+				continue
+			}
+			if isCanonical && !bytes.Equal(data, expected) {
+				t.Errorf("FAIL: CAT%03d record = %x; Expected: %x", stdUAP.Category, data, expected)
+				continue
+			}
+			again := NewRecord()
+			if unRead, err := again.Decode(data, stdUAP); err != nil || unRead != 0 || !sameItems(again.Items, rec.Items) {
+				t.Errorf("FAIL: CAT%03d %x decoded again = %v, error = %v; Expected: %v", stdUAP.Category, data,
+					again.String(), err, rec.String())
+			}
+		}
+	}
+	if nbOfRecords < minRecords {
+		t.Errorf("FAIL: %v records decoded; Expected: at least %v", nbOfRecords, minRecords)
+	} else {
+		t.Logf("SUCCESS: %v records decoded", nbOfRecords)
+	}
+}
+
+// TestRoundTrip_DataBlocks checks that every data block decoded from the samples of the tests and examples/data
+// is encoded again (DataBlock.Payload) to the bytes decoded, and encoded (DataBlock.Encode) to the bytes decoded
+// and decoded again with the same records.
+// A data block which is not canonical (see canonicalItems) can be rejected by the encoding or encoded to other bytes.
+func TestRoundTrip_DataBlocks(t *testing.T) {
+	// Arrange
+	samples := testSamples(t)
+	data, err := os.ReadFile("examples/data/sample.ast")
+	if err != nil {
+		t.Fatalf("FAIL: error = %v; Expected: %v", err, nil)
+	}
+	samples = append(samples, data)
+	samples = append(samples, pcapDatagrams(t, "examples/data/data_bhm.pcap")...)
+	nbOfBlocks := 0
+	d := NewDecoder(WithStrict())
+
+	for _, sample := range samples {
+		for offset := 0; offset < len(sample); {
+			db, _, err := d.DecodeDataBlock(sample[offset:])
+			if err != nil {
+				break
+			}
+			nbOfBlocks++
+			expected := sample[offset : offset+int(db.Len)]
+			offset += int(db.Len)
+
+			// Act
+			payload := bytes.Join(db.Payload(), nil)
+			data, err := db.Encode()
+
+			// Assert
+			if !bytes.Equal(payload, expected) {
+				t.Errorf("FAIL: CAT%03d payload = %x; Expected: %x", db.Category, payload, expected)
+			}
+			if err := checkDataBlock(db, data, err, expected); err != "" {
+				t.Errorf("FAIL: CAT%03d %s", db.Category, err)
+			}
+		}
+	}
+	if nbOfBlocks < minBlocks {
+		t.Errorf("FAIL: %v data blocks decoded; Expected: at least %v", nbOfBlocks, minBlocks)
+	} else {
+		t.Logf("SUCCESS: %v data blocks decoded", nbOfBlocks)
+	}
+}
+
+// checkDataBlock checks the encoding data (error err) of the data block db decoded from expected with a strict Decoder,
+// it returns the failure, "" if none.
+func checkDataBlock(db *DataBlock, data []byte, err error, expected []byte) string {
+	isCanonical := true
+	for _, w := range db.Warnings {
+		isCanonical = isCanonical && w.Kind != WarnLenInconsistent
+	}
+	for _, rec := range db.Records {
+		isCanonical = isCanonical && canonicalItems(rec.Fspec, rec.Items)
+	}
+	if err != nil {
+		if isCanonical {
+			return fmt.Sprintf("%x - error = %v; Expected: %v", expected, err, nil)
+		}
+		return ""
+	}
+	if isCanonical && !bytes.Equal(data, expected) {
+		return fmt.Sprintf("data block = %x; Expected: %x", data, expected)
+	}
+
+	again := NewDataBlock()
+	unRead, err := again.Decode(data)
+	if err != nil || unRead != 0 || again.Category != db.Category || len(again.Records) != len(db.Records) {
+		return fmt.Sprintf("%x decoded again: %d records, error = %v; Expected: %d records", data, len(again.Records),
+			err, len(db.Records))
+	}
+	for i, rec := range again.Records {
+		if !sameItems(rec.Items, db.Records[i].Items) {
+			return fmt.Sprintf("%x decoded again: record %d = %v; Expected: %v", data, i, rec.String(),
+				db.Records[i].String())
+		}
+	}
+	return ""
+}
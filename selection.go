@@ -0,0 +1,53 @@
+package goasterix
+
+// selection is the set of items decoded for a category, see WithSelection.
+type selection struct {
+	frns  map[uint8]bool
+	names map[string]bool
+}
+
+// selected returns true if the data field pf is selected by its FRN or its name (DataItem).
+func (s *selection) selected(pf *planField) bool {
+	return s.frns[pf.field.FRN] || s.names[pf.field.DataItem]
+}
+
+// WithSelection decodes only the items of a category selected by their name (DataItem of the UAP),
+// e.g. WithSelection(62, "I062/105", "I062/136"). The other items are skipped by their length without being
+// allocated: the records contain only the selected items and their FSPEC is rebuilt from them, the records
+// are encoded (see Record.Payload and DataBlock.Encode) with the selected items only.
+// The discriminating item of a conditional UAP (see uap.Condition) is always decoded.
+// The malformed items are detected like the decoding without selection. The names absent of the UAP are ignored,
+// the categories without selection are fully decoded. The selections of a category are cumulative.
+func WithSelection(category uint8, dataItems ...string) DecoderOption {
+	return func(d *Decoder) {
+		s := d.plans.selection(category)
+		for _, name := range dataItems {
+			s.names[name] = true
+		}
+	}
+}
+
+// WithSelectionFRN decodes only the items of a category selected by their FRN, like WithSelection.
+// The FRNs of the variants of a conditional UAP (see uap.Condition) are the FRNs of the records.
+func WithSelectionFRN(category uint8, frns ...uint8) DecoderOption {
+	return func(d *Decoder) {
+		s := d.plans.selection(category)
+		for _, frn := range frns {
+			s.frns[frn] = true
+		}
+	}
+}
+
+// selection returns the selection of a category, it is created on its first use.
+// The selections are only modified by the options of NewDecoder, before any plan is compiled.
+func (pc *planCache) selection(category uint8) *selection {
+	if pc.selections == nil {
+		pc.selections = make(map[uint8]*selection)
+	}
+	s, found := pc.selections[category]
+	if !found {
+		s = &selection{frns: make(map[uint8]bool), names: make(map[string]bool)}
+		pc.selections[category] = s
+	}
+	return s
+}
@@ -0,0 +1,202 @@
+package goasterix
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/mokhtarimokhtar/goasterix/uap"
+	"github.com/mokhtarimokhtar/goasterix/util"
+)
+
+func TestDecoderSelection(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		input        string
+		options      []DecoderOption
+		dataItems    []string // of each record
+		err          error
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "CAT062 by name",
+			input:        cat062Len1351,
+			options:      []DecoderOption{WithSelection(62, "I062/105", "I062/136")},
+			dataItems:    []string{"I062/105", "I062/136"},
+			err:          nil,
+		},
+		{
+			TestCaseName: "CAT034 by FRN",
+			input:        "220014f6083602429b7110940028200094008000",
+			options:      []DecoderOption{WithSelectionFRN(34, 1, 3)},
+			dataItems:    []string{"I034/010", "I034/030"},
+			err:          nil,
+		},
+		{
+			TestCaseName: "cumulative selections",
+			input:        "220014f6083602429b7110940028200094008000",
+			options:      []DecoderOption{WithSelectionFRN(34, 1), WithSelection(34, "I034/030", "I999/999")},
+			dataItems:    []string{"I034/010", "I034/030"},
+			err:          nil,
+		},
+		{
+			TestCaseName: "category without selection",
+			input:        "220014f6083602429b7110940028200094008000",
+			options:      []DecoderOption{WithSelection(48, "I048/010")},
+			dataItems:    []string{"I034/010", "I034/000", "I034/030", "I034/020", "I034/050", "I034/060"},
+			err:          nil,
+		},
+		{
+			TestCaseName: "skipped item truncated",
+			input:        "220013f6083602429b71109400282000940080",
+			options:      []DecoderOption{WithSelectionFRN(34, 1)},
+			dataItems:    []string{"I034/010"},
+			err:          io.EOF,
+		},
+	}
+
+	for _, row := range dataSet {
+		// Arrange
+		data, _ := util.HexStringToByte(row.input)
+		d := NewDecoder(row.options...)
+
+		// Act
+		db, _, err := d.DecodeDataBlock(data)
+
+		// Assert
+		if !errors.Is(err, row.err) || len(db.Records) == 0 {
+			t.Errorf("FAIL: %s - error = %v, nbOfRecords = %v; Expected: %v", row.TestCaseName, err, len(db.Records), row.err)
+			continue
+		}
+		ok := true
+		for _, rec := range db.Records {
+			var dataItems []string
+			for _, item := range rec.Items {
+				dataItems = append(dataItems, item.Meta.DataItem)
+			}
+			if len(dataItems) != len(row.dataItems) {
+				ok = false
+				t.Errorf("FAIL: %s - items = %v; Expected: %v", row.TestCaseName, dataItems, row.dataItems)
+				break
+			}
+			for i := range dataItems {
+				if dataItems[i] != row.dataItems[i] {
+					ok = false
+					t.Errorf("FAIL: %s - items = %v; Expected: %v", row.TestCaseName, dataItems, row.dataItems)
+					break
+				}
+			}
+		}
+		if ok {
+			t.Logf("SUCCESS: %s - items = %v, nbOfRecords = %v", row.TestCaseName, row.dataItems, len(db.Records))
+		}
+	}
+}
+
+func TestDecoderSelection_Condition(t *testing.T) {
+	// Arrange
+	// FRN 10 discriminates the variants of Cat4Test, FRN 11 of the track variant is selected:
+	// the discriminating item is decoded too
+	data, _ := util.HexStringToByte("01 38 80ff ffff")
+	d := NewDecoder(WithProfile(uap.Cat4Test), WithSelectionFRN(26, 11))
+	p, _ := d.Plan(26)
+	rec := NewRecord()
+
+	// Act
+	unRead, err := rec.DecodePlan(data, p)
+
+	// Assert
+	if err != nil || unRead != 0 || len(rec.Items) != 2 || rec.Items[0].Meta.FRN != 10 || rec.Items[1].Meta.FRN != 11 ||
+		!bytes.Equal(rec.Fspec, []byte{0x01, 0x30}) {
+		t.Errorf("FAIL: error = %v, unRead = %v, items = %v; Expected: %v, %v, FSPEC 0130, FRN 10 and 11", err, unRead, rec.String(), nil, 0)
+	} else {
+		t.Logf("SUCCESS: items = %v", rec.String())
+	}
+}
+
+func TestDecoderSelection_Encode(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		input        string
+		options      []DecoderOption
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "CAT048 without I048/250",
+			input:        packInput[:0x118*2],
+			options:      []DecoderOption{WithSelection(48, "I048/010", "I048/140", "I048/040", "I048/161")},
+		},
+		{
+			TestCaseName: "CAT048 with aliasing",
+			input:        packInput[:0x118*2],
+			options:      []DecoderOption{WithAliasing(), WithSelectionFRN(48, 1, 2, 4, 10, 13)},
+		},
+		{
+			TestCaseName: "CAT062 second FSPEC octet",
+			input:        cat062Len1351,
+			options:      []DecoderOption{WithSelection(62, "I062/105", "I062/136")},
+		},
+	}
+
+	for _, row := range dataSet {
+		// Arrange
+		data, _ := util.HexStringToByte(row.input)
+		db, _, err := NewDecoder(row.options...).DecodeDataBlock(data)
+		if err != nil {
+			t.Fatalf("FAIL: %s - error = %v; Expected: %v", row.TestCaseName, err, nil)
+		}
+
+		// Act
+		encoded, errEncode := db.Encode()
+		output, unRead, errDecode := NewDecoder().DecodeDataBlock(encoded)
+
+		// Assert
+		if errEncode != nil || errDecode != nil || unRead != 0 || len(output.Records) != len(db.Records) {
+			t.Errorf("FAIL: %s - errors = %v, %v, unRead = %v; Expected: %v, %v, %v", row.TestCaseName, errEncode, errDecode, unRead, nil, nil, 0)
+			continue
+		}
+		ok := true
+		for i, rec := range output.Records {
+			if !bytes.Equal(rec.Payload(), db.Records[i].Payload()) || len(rec.Items) != len(db.Records[i].Items) {
+				ok = false
+				t.Errorf("FAIL: %s - record %d = %v; Expected: %v", row.TestCaseName, i, rec.String(), db.Records[i].String())
+			}
+		}
+		if ok {
+			t.Logf("SUCCESS: %s - %d records decoded again", row.TestCaseName, len(output.Records))
+		}
+	}
+}
+
+func TestDecoderSelection_Payload(t *testing.T) {
+	// Arrange
+	data, _ := util.HexStringToByte(packInput[:0x118*2])
+	db, _, err := NewDecoder(WithSelection(48, "I048/010", "I048/140")).DecodeDataBlock(data)
+	if err != nil {
+		t.Fatalf("FAIL: error = %v; Expected: %v", err, nil)
+	}
+	lenBefore := db.Len
+	removed := len(db.Records[0].Items[1].Payload()) // I048/140, FRN 2
+
+	// Act
+	payload := bytes.Join(db.Payload(), nil)
+	output := NewDataBlock()
+	unRead, errDecode := output.Decode(payload)
+	errDelete := db.Records[0].Delete(2)
+
+	// Assert
+	if errDecode != nil || unRead != 0 || int(lenBefore) != len(payload) || len(output.Records) != len(db.Records) {
+		t.Errorf("FAIL: error = %v, unRead = %v, Len = %v, nbOfRecords = %v; Expected: %v, %v, %v, %v",
+			errDecode, unRead, lenBefore, len(output.Records), nil, 0, len(payload), len(db.Records))
+	} else {
+		t.Logf("SUCCESS: Len = %v, nbOfRecords = %v", lenBefore, len(output.Records))
+	}
+	if errDelete != nil || int(db.Len) != len(payload)-removed {
+		t.Errorf("FAIL: error = %v, Len = %v; Expected: %v, %v", errDelete, db.Len, nil, len(payload)-removed)
+	} else {
+		t.Logf("SUCCESS: Len = %v after Delete", db.Len)
+	}
+}
@@ -0,0 +1,204 @@
+package goasterix
+
+import (
+	"fmt"
+
+	"github.com/mokhtarimokhtar/goasterix/uap"
+)
+
+// WarningKind is a violation of the ASTERIX specification reported by the strict mode (see WithStrict).
+type WarningKind uint8
+
+const (
+	// WarnLenInconsistent reports that the records end before the LEN of the data block,
+	// the remaining bytes of the data block are zero and decoded as empty records.
+	WarnLenInconsistent WarningKind = iota + 1
+
+	// WarnPadding reports zero bytes following the data block (LEN) at the end of the data.
+	WarnPadding
+
+	// WarnFspecTrailingZero reports a FSPEC (or the primary subfield of a compound item) ending with a zero octet:
+	// the previous octet has its FX bit set without any following item.
+	WarnFspecTrailingZero
+
+	// WarnSpareBits reports a spare bit set, the spare bits are the bits of a data field with a bit-level
+	// definition (uap.SubField) which are not declared by a subfield, except the FX bits of an Extended field.
+	// The data fields without SubFields are not checked: among the profiles shipped, only CAT034 and CAT048
+	// declare them. The subfields of Compound items, of RE and SP items with sub-profile and the fields
+	// of a Random Field Sequencing are checked too.
+	WarnSpareBits
+
+	// WarnExplicitSize reports an Explicit item whose length indicator differs from the size of its definition
+	// (uap.ExplicitField.Size), the size is set by uap/loader when the specification defines the content.
+	WarnExplicitSize
+
+	// WarnRepetitionZero reports a Repetitive item with a factor REP of 0.
+	WarnRepetitionZero
+)
+
+var warningKinds = map[WarningKind]string{
+	WarnLenInconsistent:   "LEN inconsistent with the records",
+	WarnPadding:           "padding after the data block",
+	WarnFspecTrailingZero: "trailing zero octet with FX set",
+	WarnSpareBits:         "spare bits set",
+	WarnExplicitSize:      "explicit length does not match its definition",
+	WarnRepetitionZero:    "repetition factor REP=0",
+}
+
+func (k WarningKind) String() string {
+	if s, found := warningKinds[k]; found {
+		return s
+	}
+	return fmt.Sprintf("warning %d", uint8(k))
+}
+
+// Warning reports a violation of the ASTERIX specification which does not prevent the decoding.
+// Offset is the position in byte, from the CAT field of the data block, of the data field (of the FSPEC if FRN
+// is 0 and of the record if DataItem is empty), Record is the index of the record in the data block.
+type Warning struct {
+	Kind     WarningKind
+	Offset   int
+	Category uint8
+	Record   int
+	FRN      uint8
+	DataItem string
+}
+
+func (w Warning) String() string {
+	field := "FSPEC"
+	if w.FRN != 0 {
+		field = fmt.Sprintf("FRN %d", w.FRN)
+	}
+	if w.DataItem != "" {
+		field += " " + w.DataItem
+	}
+	return fmt.Sprintf("[ASTERIX] CAT%03d record %d at offset %d, %s: %v", w.Category, w.Record, w.Offset, field, w.Kind)
+}
+
+// WithStrict enables the strict mode: the decoding is unchanged but the violations of the specification accepted
+// by the decoding are reported in DataBlock.Warnings, e.g. to check the conformance of a sensor output.
+func WithStrict() DecoderOption {
+	return func(d *Decoder) {
+		d.strict = true
+	}
+}
+
+// fspecWarning returns the warning of a FSPEC (or compound primary subfield) ending with a zero octet.
+func fspecWarning(fspec []byte) (WarningKind, bool) {
+	if len(fspec) > 1 && fspec[len(fspec)-1] == 0 {
+		return WarnFspecTrailingZero, true
+	}
+	return 0, false
+}
+
+// itemWarnings returns the violations of the specification of a decoded item defined by field,
+// The fields of a Random Field Sequencing are checked with their compiled data field in fields.
+func itemWarnings(item *Item, field uap.DataField, fields []planField) []WarningKind {
+	var kinds []WarningKind
+	switch field.Type {
+	case uap.Fixed:
+		if spareBitsSet(item.Fixed.Data, field.SubFields, 0, false) {
+			kinds = append(kinds, WarnSpareBits)
+		}
+
+	case uap.Extended:
+		parts := [][]byte{item.Extended.Primary}
+		size := int(field.Extended.SecondarySize)
+		for i := 0; size != 0 && i+size <= len(item.Extended.Secondary); i += size {
+			parts = append(parts, item.Extended.Secondary[i:i+size])
+		}
+		for i, data := range parts {
+			if spareBitsSet(data, field.SubFields, uint8(i), true) {
+				kinds = append(kinds, WarnSpareBits)
+				break
+			}
+		}
+
+	case uap.Explicit:
+		if field.Explicit.Size != 0 && item.Explicit.Len != field.Explicit.Size {
+			kinds = append(kinds, WarnExplicitSize)
+		}
+		if spareBitsSet(item.Explicit.Data, field.SubFields, 0, false) {
+			kinds = append(kinds, WarnSpareBits)
+		}
+
+	case uap.Repetitive:
+		if item.Repetitive.Rep == 0 {
+			kinds = append(kinds, WarnRepetitionZero)
+		}
+		size := int(field.Repetitive.SubItemSize)
+		for i := 0; size != 0 && i+size <= len(item.Repetitive.Data); i += size {
+			if spareBitsSet(item.Repetitive.Data[i:i+size], field.SubFields, 0, false) {
+				kinds = append(kinds, WarnSpareBits)
+				break
+			}
+		}
+
+	case uap.Compound:
+		kinds = append(kinds, compoundWarnings(item.Compound, field.Compound)...)
+
+	case uap.SP, uap.RE:
+		if item.SP.Compound != nil {
+			kinds = append(kinds, compoundWarnings(item.SP.Compound, field.Compound)...)
+		}
+
+	case uap.RFS:
+		for i := range item.RFS.Sequence {
+			rf := &item.RFS.Sequence[i]
+			if pf := rfsPlanField(rf.FRN, fields); pf != nil {
+				kinds = append(kinds, itemWarnings(&rf.Field, pf.field, nil)...)
+			}
+		}
+	}
+	return kinds
+}
+
+// compoundWarnings returns the violations of the specification of the subfields of a compound defined by subFields.
+func compoundWarnings(cp *Compound, subFields []uap.DataField) []WarningKind {
+	var kinds []WarningKind
+	if kind, found := fspecWarning(cp.Primary); found {
+		kinds = append(kinds, kind)
+	}
+	for i := range cp.Secondary {
+		sub := &cp.Secondary[i]
+		frn := sub.Meta.FRN
+		if frn == 0 || int(frn) > len(subFields) {
+			continue
+		}
+		kinds = append(kinds, itemWarnings(sub, subFields[frn-1], nil)...)
+	}
+	return kinds
+}
+
+// spareBitsSet returns true if a bit of data not declared by the subfields of part is set.
+// The part is checked only if it has subfields, fx excludes the FX bit (bit 1).
+func spareBitsSet(data []byte, subFields []uap.SubField, part uint8, fx bool) bool {
+	n := len(data) * 8
+	mask := make([]byte, len(data))
+	declared := false
+	for _, sf := range subFields {
+		if sf.Part != part {
+			continue
+		}
+		declared = true
+		for b := int(sf.To); b <= int(sf.From) && b <= n; b++ {
+			if b == 0 {
+				continue
+			}
+			i := n - b // position from the most significant bit of data
+			mask[i/8] |= 0x80 >> uint(i%8)
+		}
+	}
+	if !declared || n == 0 {
+		return false
+	}
+	if fx {
+		mask[len(mask)-1] |= 0x01
+	}
+	for i, b := range data {
+		if b&^mask[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
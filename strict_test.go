@@ -0,0 +1,267 @@
+package goasterix
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mokhtarimokhtar/goasterix/uap"
+	"github.com/mokhtarimokhtar/goasterix/util"
+)
+
+var strictTestUAP = uap.StandardUAP{
+	Name:     "cat026_strict",
+	Category: 26,
+	Items: []uap.DataField{
+		{
+			FRN: 1, DataItem: "I026/001", Type: uap.Fixed, Fixed: uap.FixedField{Size: 1},
+			SubFields: []uap.SubField{{Name: "A", From: 8, To: 2}},
+		},
+		{
+			FRN: 2, DataItem: "I026/002", Type: uap.Extended, Extended: uap.ExtendedField{PrimarySize: 1, SecondarySize: 1},
+			SubFields: []uap.SubField{{Name: "B", From: 8, To: 3}},
+		},
+		{FRN: 3, DataItem: "I026/003", Type: uap.Explicit, Explicit: uap.ExplicitField{Size: 3}},
+		{FRN: 4, DataItem: "I026/004", Type: uap.Repetitive, Repetitive: uap.RepetitiveField{SubItemSize: 1}},
+		{FRN: 5, DataItem: "I026/005", Type: uap.Compound, Compound: []uap.DataField{
+			{FRN: 1, DataItem: "Compound/001", Type: uap.Fixed, Fixed: uap.FixedField{Size: 1}},
+		}},
+		{FRN: 6, DataItem: "I026/006", Type: uap.RFS},
+		{FRN: 7, DataItem: "I026/SP", Type: uap.SP},
+	},
+	SpecialPurpose: []uap.DataField{
+		{
+			FRN: 1, DataItem: "SP/001", Type: uap.Fixed, Fixed: uap.FixedField{Size: 1},
+			SubFields: []uap.SubField{{Name: "C", From: 8, To: 2}},
+		},
+	},
+}
+
+func TestDecoderStrict_Warnings(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		input        string
+		warnings     []Warning
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "conformant record",
+			input:        "1a 000d f8 fe fc 03ffff 01ff 80ff",
+			warnings:     nil,
+		},
+		{
+			TestCaseName: "spare bit of fixed item",
+			input:        "1a 0005 80 01",
+			warnings:     []Warning{{Kind: WarnSpareBits, Offset: 4, Category: 26, FRN: 1, DataItem: "I026/001"}},
+		},
+		{
+			TestCaseName: "spare bit of extended item",
+			input:        "1a 0005 40 02",
+			warnings:     []Warning{{Kind: WarnSpareBits, Offset: 4, Category: 26, FRN: 2, DataItem: "I026/002"}},
+		},
+		{
+			TestCaseName: "explicit length",
+			input:        "1a 0006 20 02ff",
+			warnings:     []Warning{{Kind: WarnExplicitSize, Offset: 4, Category: 26, FRN: 3, DataItem: "I026/003"}},
+		},
+		{
+			TestCaseName: "REP=0",
+			input:        "1a 0005 10 00",
+			warnings:     []Warning{{Kind: WarnRepetitionZero, Offset: 4, Category: 26, FRN: 4, DataItem: "I026/004"}},
+		},
+		{
+			TestCaseName: "compound primary subfield with trailing zero octet",
+			input:        "1a 0006 08 0100",
+			warnings:     []Warning{{Kind: WarnFspecTrailingZero, Offset: 4, Category: 26, FRN: 5, DataItem: "I026/005"}},
+		},
+		{
+			TestCaseName: "spare bit of a field of random field sequencing",
+			input:        "1a 0007 04 01 01 01",
+			warnings:     []Warning{{Kind: WarnSpareBits, Offset: 4, Category: 26, FRN: 6, DataItem: "I026/006"}},
+		},
+		{
+			TestCaseName: "spare bit of a subfield of special purpose",
+			input:        "1a 0007 02 03 80 01",
+			warnings:     []Warning{{Kind: WarnSpareBits, Offset: 4, Category: 26, FRN: 7, DataItem: "I026/SP"}},
+		},
+		{
+			TestCaseName: "FSPEC with trailing zero octet",
+			input:        "1a 0006 8100 fe",
+			warnings:     []Warning{{Kind: WarnFspecTrailingZero, Offset: 3, Category: 26}},
+		},
+		{
+			TestCaseName: "LEN with zero padding",
+			input:        "1a 0007 80fe 0000",
+			warnings:     []Warning{{Kind: WarnLenInconsistent, Offset: 5, Category: 26, Record: 1}},
+		},
+		{
+			TestCaseName: "zero padding after LEN",
+			input:        "1a 0005 80fe 0000",
+			warnings:     []Warning{{Kind: WarnPadding, Offset: 5, Category: 26, Record: 1}},
+		},
+		{
+			TestCaseName: "second record",
+			input:        "1a 0007 80fe 8001",
+			warnings:     []Warning{{Kind: WarnSpareBits, Offset: 6, Category: 26, Record: 1, FRN: 1, DataItem: "I026/001"}},
+		},
+	}
+	d := NewDecoder(WithProfile(strictTestUAP), WithStrict())
+
+	for _, row := range dataSet {
+		// Arrange
+		data, _ := util.HexStringToByte(row.input)
+
+		// Act
+		db, _, err := d.DecodeDataBlock(data)
+
+		// Assert
+		if err != nil {
+			t.Errorf("FAIL: %s - error = %v; Expected: %v", row.TestCaseName, err, nil)
+		}
+		if !reflect.DeepEqual(db.Warnings, row.warnings) {
+			t.Errorf("FAIL: %s - warnings = %v; Expected: %v", row.TestCaseName, db.Warnings, row.warnings)
+		} else {
+			t.Logf("SUCCESS: warnings = %v; Expected: %v", db.Warnings, row.warnings)
+		}
+	}
+}
+
+func TestDecoderStrict_Cat034(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		input        string
+		warnings     []Warning
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "conformant record",
+			input:        "220014f6083602429b7110940028200094008000",
+			warnings:     nil,
+		},
+		{
+			TestCaseName: "spare bit of I034/050 PSR",
+			input:        "220014f6083602429b7110940029200094008000",
+			warnings:     []Warning{{Kind: WarnSpareBits, Offset: 11, Category: 34, FRN: 6, DataItem: "I034/050"}},
+		},
+	}
+	d := NewDecoder(WithStrict())
+
+	for _, row := range dataSet {
+		// Arrange
+		data, _ := util.HexStringToByte(row.input)
+
+		// Act
+		db, _, err := d.DecodeDataBlock(data)
+
+		// Assert
+		if err != nil {
+			t.Errorf("FAIL: %s - error = %v; Expected: %v", row.TestCaseName, err, nil)
+		}
+		if !reflect.DeepEqual(db.Warnings, row.warnings) {
+			t.Errorf("FAIL: %s - warnings = %v; Expected: %v", row.TestCaseName, db.Warnings, row.warnings)
+		} else {
+			t.Logf("SUCCESS: warnings = %v; Expected: %v", db.Warnings, row.warnings)
+		}
+	}
+}
+
+func TestDecoderDecodeRecord_Strict(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		category     uint8
+		input        string
+		unRead       int
+		warnings     []Warning
+		err          error
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "conformant record",
+			category:     26,
+			input:        "f8 fe fc 03ffff 01ff 80ff",
+			unRead:       0,
+			warnings:     nil,
+			err:          nil,
+		},
+		{
+			TestCaseName: "explicit length",
+			category:     26,
+			input:        "20 02ff",
+			unRead:       0,
+			warnings:     []Warning{{Kind: WarnExplicitSize, Offset: 1, Category: 26, FRN: 3, DataItem: "I026/003"}},
+			err:          nil,
+		},
+		{
+			TestCaseName: "FSPEC with trailing zero octet",
+			category:     26,
+			input:        "8100 fe",
+			unRead:       0,
+			warnings:     []Warning{{Kind: WarnFspecTrailingZero, Category: 26}},
+			err:          nil,
+		},
+		{
+			TestCaseName: "unknown category",
+			category:     27,
+			input:        "80fe",
+			unRead:       2,
+			warnings:     nil,
+			err:          ErrCategoryUnknown,
+		},
+	}
+	d := NewDecoder(WithProfile(strictTestUAP), WithStrict())
+
+	for _, row := range dataSet {
+		// Arrange
+		data, _ := util.HexStringToByte(row.input)
+
+		// Act
+		_, unRead, warnings, err := d.DecodeRecord(row.category, data)
+
+		// Assert
+		if err != row.err || unRead != row.unRead {
+			t.Errorf("FAIL: %s - error = %v, unRead = %v; Expected: %v, %v", row.TestCaseName, err, unRead, row.err, row.unRead)
+		}
+		if !reflect.DeepEqual(warnings, row.warnings) {
+			t.Errorf("FAIL: %s - warnings = %v; Expected: %v", row.TestCaseName, warnings, row.warnings)
+		} else {
+			t.Logf("SUCCESS: warnings = %v; Expected: %v", warnings, row.warnings)
+		}
+	}
+}
+
+func TestDecoderStrict_Disabled(t *testing.T) {
+	// Arrange
+	data, _ := util.HexStringToByte("1a 0007 80fe 8001 0000")
+	d := NewDecoder(WithProfile(strictTestUAP))
+
+	// Act
+	db, unRead, err := d.DecodeDataBlock(data)
+
+	// Assert
+	if err != nil || unRead != 2 || len(db.Records) != 2 {
+		t.Errorf("FAIL: error = %v, unRead = %v, records = %v; Expected: %v, %v, %v", err, unRead, len(db.Records), nil, 2, 2)
+	}
+	if db.Warnings != nil {
+		t.Errorf("FAIL: warnings = %v; Expected: %v", db.Warnings, nil)
+	} else {
+		t.Logf("SUCCESS: warnings = %v; Expected: %v", db.Warnings, nil)
+	}
+}
+
+func TestWarning_String(t *testing.T) {
+	// Arrange
+	w := Warning{Kind: WarnSpareBits, Offset: 6, Category: 26, Record: 1, FRN: 1, DataItem: "I026/001"}
+	output := "[ASTERIX] CAT026 record 1 at offset 6, FRN 1 I026/001: spare bits set"
+
+	// Act
+	s := w.String()
+
+	// Assert
+	if s != output {
+		t.Errorf("FAIL: %s; Expected: %s", s, output)
+	} else {
+		t.Logf("SUCCESS: %s; Expected: %s", s, output)
+	}
+}
@@ -18,7 +18,6 @@ var Cat001V12 = StandardUAP{
 			FRN:         2,
 			DataItem:    "I001/020",
 			Description: "Target Report Descriptor",
-			Conditional: true,
 			Type:        Extended,
 			Extended: ExtendedField{
 				PrimarySize:   1,
@@ -26,6 +25,15 @@ var Cat001V12 = StandardUAP{
 			},
 		},
 	},
+	// TYP bit of I001/020 selects the plot or track UAP
+	Condition: &Condition{
+		FRN:  2,
+		Mask: 0x80,
+		Variants: map[uint8][]DataField{
+			0x00: Cat001PlotV12,
+			0x80: Cat001TrackV12,
+		},
+	},
 }
 var Cat001PlotV12 = []DataField{
 	{
@@ -1,8 +1,11 @@
+// Code generated by uapgen from cat034_1.27.json; DO NOT EDIT.
+
 package uap
 
-// Cat034V127 User Application Profile CAT034
+// Cat034V127 User Application Profile
 // version 1.27
 var Cat034V127 = StandardUAP{
+	Name:     "cat034_1.27",
 	Category: 34,
 	Version:  1.27,
 	Items: []DataField{
@@ -14,6 +17,10 @@ var Cat034V127 = StandardUAP{
 			Fixed: FixedField{
 				Size: 2,
 			},
+			SubFields: []SubField{
+				{Name: "SAC", From: 16, To: 9},
+				{Name: "SIC", From: 8, To: 1},
+			},
 		},
 		{
 			FRN:         2,
@@ -23,6 +30,17 @@ var Cat034V127 = StandardUAP{
 			Fixed: FixedField{
 				Size: 1,
 			},
+			SubFields: []SubField{
+				{Name: "000", From: 8, To: 1, Enum: map[uint64]string{
+					1: "North Marker message",
+					2: "Sector crossing message",
+					3: "Geographical filtering message",
+					4: "Jamming Strobe message",
+					5: "Solar Storm message",
+					6: "SSR Jamming Strobe message",
+					7: "Mode S Jamming Strobe message",
+				}},
+			},
 		},
 		{
 			FRN:         3,
@@ -32,6 +50,9 @@ var Cat034V127 = StandardUAP{
 			Fixed: FixedField{
 				Size: 3,
 			},
+			SubFields: []SubField{
+				{Name: "030", From: 24, To: 1, Scale: 0.0078125, Unit: "s"},
+			},
 		},
 		{
 			FRN:         4,
@@ -41,6 +62,9 @@ var Cat034V127 = StandardUAP{
 			Fixed: FixedField{
 				Size: 1,
 			},
+			SubFields: []SubField{
+				{Name: "020", From: 8, To: 1, Scale: 1.40625, Unit: "°"},
+			},
 		},
 		{
 			FRN:         5,
@@ -50,9 +74,13 @@ var Cat034V127 = StandardUAP{
 			Fixed: FixedField{
 				Size: 2,
 			},
+			SubFields: []SubField{
+				{Name: "041", From: 16, To: 1, Scale: 0.0078125, Unit: "s"},
+			},
 		},
 		{
-			FRN: 6, DataItem: "I034/050",
+			FRN:         6,
+			DataItem:    "I034/050",
 			Description: "System Configuration and Status",
 			Type:        Compound,
 			Compound: []DataField{
@@ -64,6 +92,36 @@ var Cat034V127 = StandardUAP{
 					Fixed: FixedField{
 						Size: 1,
 					},
+					SubFields: []SubField{
+						{Name: "NOGO", From: 8, To: 8, Enum: map[uint64]string{
+							0: "System is released for operational use",
+							1: "Operational use of System is inhibited",
+						}},
+						{Name: "RDPC", From: 7, To: 7, Enum: map[uint64]string{
+							0: "RDPC-1 selected",
+							1: "RDPC-2 selected",
+						}},
+						{Name: "RDPR", From: 6, To: 6, Enum: map[uint64]string{
+							0: "No reset of RDPC",
+							1: "Reset of RDPC",
+						}},
+						{Name: "OVLRDP", From: 5, To: 5, Enum: map[uint64]string{
+							0: "Default, no overload",
+							1: "Overload in RDP",
+						}},
+						{Name: "OVLXMT", From: 4, To: 4, Enum: map[uint64]string{
+							0: "Default, no overload",
+							1: "Overload in transmission subsystem",
+						}},
+						{Name: "MSC", From: 3, To: 3, Enum: map[uint64]string{
+							0: "Monitoring system connected",
+							1: "Monitoring system disconnected",
+						}},
+						{Name: "TSV", From: 2, To: 2, Enum: map[uint64]string{
+							0: "Valid",
+							1: "Invalid",
+						}},
+					},
 				},
 				{
 					FRN:  2,
@@ -81,6 +139,26 @@ var Cat034V127 = StandardUAP{
 					Fixed: FixedField{
 						Size: 1,
 					},
+					SubFields: []SubField{
+						{Name: "ANT", From: 8, To: 8, Enum: map[uint64]string{
+							0: "Antenna 1",
+							1: "Antenna 2",
+						}},
+						{Name: "CHAB", From: 7, To: 6, Enum: map[uint64]string{
+							0: "No channel selected",
+							1: "Channel A only selected",
+							2: "Channel B only selected",
+							3: "Diversity mode ; Channel A and B selected",
+						}},
+						{Name: "OVL", From: 5, To: 5, Enum: map[uint64]string{
+							0: "No overload",
+							1: "Overload",
+						}},
+						{Name: "MSC", From: 4, To: 4, Enum: map[uint64]string{
+							0: "Monitoring system connected",
+							1: "Monitoring system disconnected",
+						}},
+					},
 				},
 				{
 					FRN:         5,
@@ -90,6 +168,26 @@ var Cat034V127 = StandardUAP{
 					Fixed: FixedField{
 						Size: 1,
 					},
+					SubFields: []SubField{
+						{Name: "ANT", From: 8, To: 8, Enum: map[uint64]string{
+							0: "Antenna 1",
+							1: "Antenna 2",
+						}},
+						{Name: "CHAB", From: 7, To: 6, Enum: map[uint64]string{
+							0: "No channel selected",
+							1: "Channel A only selected",
+							2: "Channel B only selected",
+							3: "Invalid combination",
+						}},
+						{Name: "OVL", From: 5, To: 5, Enum: map[uint64]string{
+							0: "No overload",
+							1: "Overload",
+						}},
+						{Name: "MSC", From: 4, To: 4, Enum: map[uint64]string{
+							0: "Monitoring system connected",
+							1: "Monitoring system disconnected",
+						}},
+					},
 				},
 				{
 					FRN:         6,
@@ -99,6 +197,42 @@ var Cat034V127 = StandardUAP{
 					Fixed: FixedField{
 						Size: 2,
 					},
+					SubFields: []SubField{
+						{Name: "ANT", From: 16, To: 16, Enum: map[uint64]string{
+							0: "Antenna 1",
+							1: "Antenna 2",
+						}},
+						{Name: "CHAB", From: 15, To: 14, Enum: map[uint64]string{
+							0: "No channel selected",
+							1: "Channel A only selected",
+							2: "Channel B only selected",
+							3: "Illegal combination",
+						}},
+						{Name: "OVLSUR", From: 13, To: 13, Enum: map[uint64]string{
+							0: "No overload",
+							1: "Overload",
+						}},
+						{Name: "MSC", From: 12, To: 12, Enum: map[uint64]string{
+							0: "Monitoring system connected",
+							1: "Monitoring system disconnected",
+						}},
+						{Name: "SCF", From: 11, To: 11, Enum: map[uint64]string{
+							0: "Channel A in use",
+							1: "Channel B in use",
+						}},
+						{Name: "DLF", From: 10, To: 10, Enum: map[uint64]string{
+							0: "Channel A in use",
+							1: "Channel B in use",
+						}},
+						{Name: "OVLSCF", From: 9, To: 9, Enum: map[uint64]string{
+							0: "No overload",
+							1: "Overload",
+						}},
+						{Name: "OVLDLF", From: 8, To: 8, Enum: map[uint64]string{
+							0: "No overload",
+							1: "Overload",
+						}},
+					},
 				},
 				{
 					FRN:  7,
@@ -107,7 +241,8 @@ var Cat034V127 = StandardUAP{
 			},
 		},
 		{
-			FRN: 7, DataItem: "I034/060",
+			FRN:         7,
+			DataItem:    "I034/060",
 			Description: "System Processing Mode",
 			Type:        Compound,
 			Compound: []DataField{
@@ -119,6 +254,28 @@ var Cat034V127 = StandardUAP{
 					Fixed: FixedField{
 						Size: 1,
 					},
+					SubFields: []SubField{
+						{Name: "REDRDP", From: 7, To: 5, Enum: map[uint64]string{
+							0: "No reduction active",
+							1: "Reduction step 1 active",
+							2: "Reduction step 2 active",
+							3: "Reduction step 3 active",
+							4: "Reduction step 4 active",
+							5: "Reduction step 5 active",
+							6: "Reduction step 6 active",
+							7: "Reduction step 7 active",
+						}},
+						{Name: "REDXMT", From: 4, To: 2, Enum: map[uint64]string{
+							0: "No reduction active",
+							1: "Reduction step 1 active",
+							2: "Reduction step 2 active",
+							3: "Reduction step 3 active",
+							4: "Reduction step 4 active",
+							5: "Reduction step 5 active",
+							6: "Reduction step 6 active",
+							7: "Reduction step 7 active",
+						}},
+					},
 				},
 				{
 					FRN:  2,
@@ -136,6 +293,28 @@ var Cat034V127 = StandardUAP{
 					Fixed: FixedField{
 						Size: 1,
 					},
+					SubFields: []SubField{
+						{Name: "POL", From: 8, To: 8, Enum: map[uint64]string{
+							0: "Linear polarization",
+							1: "Circular polarization",
+						}},
+						{Name: "REDRAD", From: 7, To: 5, Enum: map[uint64]string{
+							0: "No reduction active",
+							1: "Reduction step 1 active",
+							2: "Reduction step 2 active",
+							3: "Reduction step 3 active",
+							4: "Reduction step 4 active",
+							5: "Reduction step 5 active",
+							6: "Reduction step 6 active",
+							7: "Reduction step 7 active",
+						}},
+						{Name: "STC", From: 4, To: 3, Enum: map[uint64]string{
+							0: "STC Map-1",
+							1: "STC Map-2",
+							2: "STC Map-3",
+							3: "STC Map-4",
+						}},
+					},
 				},
 				{
 					FRN:         5,
@@ -145,6 +324,18 @@ var Cat034V127 = StandardUAP{
 					Fixed: FixedField{
 						Size: 1,
 					},
+					SubFields: []SubField{
+						{Name: "REDRAD", From: 8, To: 6, Enum: map[uint64]string{
+							0: "No reduction active",
+							1: "Reduction step 1 active",
+							2: "Reduction step 2 active",
+							3: "Reduction step 3 active",
+							4: "Reduction step 4 active",
+							5: "Reduction step 5 active",
+							6: "Reduction step 6 active",
+							7: "Reduction step 7 active",
+						}},
+					},
 				},
 				{
 					FRN:         6,
@@ -154,6 +345,22 @@ var Cat034V127 = StandardUAP{
 					Fixed: FixedField{
 						Size: 1,
 					},
+					SubFields: []SubField{
+						{Name: "REDRAD", From: 8, To: 6, Enum: map[uint64]string{
+							0: "No reduction active",
+							1: "Reduction step 1 active",
+							2: "Reduction step 2 active",
+							3: "Reduction step 3 active",
+							4: "Reduction step 4 active",
+							5: "Reduction step 5 active",
+							6: "Reduction step 6 active",
+							7: "Reduction step 7 active",
+						}},
+						{Name: "CLU", From: 5, To: 5, Enum: map[uint64]string{
+							0: "Autonomous",
+							1: "Not autonomous",
+						}},
+					},
 				},
 				{
 					FRN:  7,
@@ -169,6 +376,28 @@ var Cat034V127 = StandardUAP{
 			Repetitive: RepetitiveField{
 				SubItemSize: 2,
 			},
+			SubFields: []SubField{
+				{Name: "TYP", From: 16, To: 12, Enum: map[uint64]string{
+					0:  "No detection (number of misses)",
+					1:  "Single PSR target reports",
+					2:  "Single SSR target reports (Non-Mode S)",
+					3:  "SSR+PSR target reports (Non-Mode S)",
+					4:  "Single All-Call target reports (Mode S)",
+					5:  "Single Roll-Call target reports (Mode S)",
+					6:  "All-Call + PSR (Mode S) target reports",
+					7:  "Roll-Call + PSR (Mode S) target reports",
+					8:  "Filter for Weather data",
+					9:  "Filter for Jamming Strobe",
+					10: "Filter for PSR data",
+					11: "Filter for SSR/Mode S data",
+					12: "Filter for SSR/Mode S+PSR data",
+					13: "Filter for Enhanced Surveillance data",
+					14: "Filter for PSR+Enhanced Surveillance",
+					15: "Filter for PSR+Enhanced Surveillance + SSR/Mode S data not in Area of Prime Interest",
+					16: "Filter for PSR+Enhanced Surveillance + all SSR/Mode S data",
+				}},
+				{Name: "COUNT", From: 11, To: 1},
+			},
 		},
 		{
 			FRN:         9,
@@ -178,6 +407,12 @@ var Cat034V127 = StandardUAP{
 			Fixed: FixedField{
 				Size: 8,
 			},
+			SubFields: []SubField{
+				{Name: "RHOST", From: 64, To: 49, Scale: 0.00390625, Unit: "NM"},
+				{Name: "RHOEND", From: 48, To: 33, Scale: 0.00390625, Unit: "NM"},
+				{Name: "THETAST", From: 32, To: 17, Scale: 0.0054931640625, Unit: "°"},
+				{Name: "THETAEND", From: 16, To: 1, Scale: 0.0054931640625, Unit: "°"},
+			},
 		},
 		{
 			FRN:         10,
@@ -187,6 +422,20 @@ var Cat034V127 = StandardUAP{
 			Fixed: FixedField{
 				Size: 1,
 			},
+			SubFields: []SubField{
+				{Name: "110", From: 8, To: 1, Enum: map[uint64]string{
+					0: "Invalid value",
+					1: "Filter for Weather data",
+					2: "Filter for Jamming Strobe",
+					3: "Filter for PSR data",
+					4: "Filter for SSR/Mode S data",
+					5: "Filter for SSR/Mode S + PSR data",
+					6: "Enhanced Surveillance data",
+					7: "Filter for PSR+Enhanced Surveillance data",
+					8: "Filter for PSR+Enhanced Surveillance + SSR/Mode S data not in Area of Prime Interest",
+					9: "Filter for PSR+Enhanced Surveillance + all SSR/Mode S data",
+				}},
+			},
 		},
 		{
 			FRN:         11,
@@ -196,6 +445,11 @@ var Cat034V127 = StandardUAP{
 			Fixed: FixedField{
 				Size: 8,
 			},
+			SubFields: []SubField{
+				{Name: "HGT", From: 64, To: 49, Signed: true, Scale: 1, Unit: "m"},
+				{Name: "LAT", From: 48, To: 25, Signed: true, Scale: 2.1457672119140625e-05, Unit: "°"},
+				{Name: "LON", From: 24, To: 1, Signed: true, Scale: 2.1457672119140625e-05, Unit: "°"},
+			},
 		},
 		{
 			FRN:         12,
@@ -205,6 +459,10 @@ var Cat034V127 = StandardUAP{
 			Fixed: FixedField{
 				Size: 2,
 			},
+			SubFields: []SubField{
+				{Name: "RNG", From: 16, To: 9, Signed: true, Scale: 0.0078125, Unit: "NM"},
+				{Name: "AZM", From: 8, To: 1, Signed: true, Scale: 0.02197265625, Unit: "°"},
+			},
 		},
 		{
 			FRN:         13,
@@ -15,6 +15,10 @@ var Cat048V127 = StandardUAP{
 			Fixed: FixedField{
 				Size: 2,
 			},
+			SubFields: []SubField{
+				{Name: "SAC", From: 16, To: 9},
+				{Name: "SIC", From: 8, To: 1},
+			},
 		},
 		{
 			FRN:         2,
@@ -24,6 +28,9 @@ var Cat048V127 = StandardUAP{
 			Fixed: FixedField{
 				Size: 3,
 			},
+			SubFields: []SubField{
+				{Name: "TOD", From: 24, To: 1, Scale: 1.0 / 128, Unit: "s"},
+			},
 		},
 		{
 			FRN:         3,
@@ -34,6 +41,28 @@ var Cat048V127 = StandardUAP{
 				PrimarySize:   1,
 				SecondarySize: 1,
 			},
+			SubFields: []SubField{
+				{Name: "TYP", From: 8, To: 6, Enum: map[uint64]string{
+					0: "no_detection",
+					1: "single_psr_detection",
+					2: "single_ssr_detection",
+					3: "ssr_psr_detection",
+					4: "single_modes_all_call",
+					5: "single_modes_roll_call",
+					6: "modes_all_call_psr",
+					7: "modes_roll_call_psr",
+				}},
+				{Name: "SIM", From: 5, To: 5},
+				{Name: "RDP", From: 4, To: 4},
+				{Name: "SPI", From: 3, To: 3},
+				{Name: "RAB", From: 2, To: 2},
+				{Name: "TST", From: 8, To: 8, Part: 1},
+				{Name: "ERR", From: 7, To: 7, Part: 1},
+				{Name: "XPP", From: 6, To: 6, Part: 1},
+				{Name: "ME", From: 5, To: 5, Part: 1},
+				{Name: "MI", From: 4, To: 4, Part: 1},
+				{Name: "FOEFRI", From: 3, To: 2, Part: 1},
+			},
 		},
 		{
 			FRN:         4,
@@ -43,6 +72,10 @@ var Cat048V127 = StandardUAP{
 			Fixed: FixedField{
 				Size: 4,
 			},
+			SubFields: []SubField{
+				{Name: "RHO", From: 32, To: 17, Scale: 1.0 / 256, Unit: "NM"},
+				{Name: "THETA", From: 16, To: 1, Scale: 360.0 / 65536, Unit: "deg"},
+			},
 		},
 		{
 			FRN:         5,
@@ -52,6 +85,12 @@ var Cat048V127 = StandardUAP{
 			Fixed: FixedField{
 				Size: 2,
 			},
+			SubFields: []SubField{
+				{Name: "V", From: 16, To: 16, Enum: map[uint64]string{0: "code_validated", 1: "code_not_validated"}},
+				{Name: "G", From: 15, To: 15, Enum: map[uint64]string{0: "default", 1: "garbled_code"}},
+				{Name: "L", From: 14, To: 14, Enum: map[uint64]string{0: "code_derived_from_transponder", 1: "code_not_extracted"}},
+				{Name: "MODE3A", From: 12, To: 1, Format: Octal},
+			},
 		},
 		{
 			FRN:         6,
@@ -61,6 +100,11 @@ var Cat048V127 = StandardUAP{
 			Fixed: FixedField{
 				Size: 2,
 			},
+			SubFields: []SubField{
+				{Name: "V", From: 16, To: 16, Enum: map[uint64]string{0: "code_validated", 1: "code_not_validated"}},
+				{Name: "G", From: 15, To: 15, Enum: map[uint64]string{0: "default", 1: "garbled_code"}},
+				{Name: "FL", From: 14, To: 1, Signed: true, Scale: 0.25, Unit: "FL"},
+			},
 		},
 		{
 			FRN:         7,
@@ -76,6 +120,9 @@ var Cat048V127 = StandardUAP{
 					Fixed: FixedField{
 						Size: 1,
 					},
+					SubFields: []SubField{
+						{Name: "SRL", From: 8, To: 1, Scale: 360.0 / 8192, Unit: "deg"},
+					},
 				},
 				{
 					FRN:         2,
@@ -85,6 +132,9 @@ var Cat048V127 = StandardUAP{
 					Fixed: FixedField{
 						Size: 1,
 					},
+					SubFields: []SubField{
+						{Name: "SRR", From: 8, To: 1},
+					},
 				},
 				{
 					FRN:         3,
@@ -94,6 +144,9 @@ var Cat048V127 = StandardUAP{
 					Fixed: FixedField{
 						Size: 1,
 					},
+					SubFields: []SubField{
+						{Name: "SAM", From: 8, To: 1, Signed: true, Unit: "dBm"},
+					},
 				},
 				{
 					FRN:         4,
@@ -103,6 +156,9 @@ var Cat048V127 = StandardUAP{
 					Fixed: FixedField{
 						Size: 1,
 					},
+					SubFields: []SubField{
+						{Name: "PRL", From: 8, To: 1, Scale: 360.0 / 8192, Unit: "deg"},
+					},
 				},
 				{
 					FRN:         5,
@@ -112,6 +168,9 @@ var Cat048V127 = StandardUAP{
 					Fixed: FixedField{
 						Size: 1,
 					},
+					SubFields: []SubField{
+						{Name: "PAM", From: 8, To: 1, Signed: true, Unit: "dBm"},
+					},
 				},
 				{
 					FRN:         6,
@@ -121,6 +180,9 @@ var Cat048V127 = StandardUAP{
 					Fixed: FixedField{
 						Size: 1,
 					},
+					SubFields: []SubField{
+						{Name: "RPD", From: 8, To: 1, Signed: true, Scale: 1.0 / 256, Unit: "NM"},
+					},
 				},
 				{
 					FRN:         7,
@@ -130,6 +192,9 @@ var Cat048V127 = StandardUAP{
 					Fixed: FixedField{
 						Size: 1,
 					},
+					SubFields: []SubField{
+						{Name: "APD", From: 8, To: 1, Signed: true, Scale: 360.0 / 16384, Unit: "deg"},
+					},
 				},
 			},
 		},
@@ -142,6 +207,9 @@ var Cat048V127 = StandardUAP{
 			Fixed: FixedField{
 				Size: 3,
 			},
+			SubFields: []SubField{
+				{Name: "ADDRESS", From: 24, To: 1, Format: Hex},
+			},
 		},
 		{
 			FRN:         9,
@@ -151,6 +219,9 @@ var Cat048V127 = StandardUAP{
 			Fixed: FixedField{
 				Size: 6,
 			},
+			SubFields: []SubField{
+				{Name: "IDENT", From: 48, To: 1, Format: ICAO6},
+			},
 		},
 		{
 			FRN:         10,
@@ -169,6 +240,9 @@ var Cat048V127 = StandardUAP{
 			Fixed: FixedField{
 				Size: 2,
 			},
+			SubFields: []SubField{
+				{Name: "TRN", From: 12, To: 1},
+			},
 		},
 		{
 			FRN:         12,
@@ -131,13 +131,20 @@ var Cat4Test = StandardUAP{
 			FRN:         10,
 			DataItem:    "I026/010",
 			Description: "Fixed type field for test",
-			Conditional: true,
 			Type:        Fixed,
 			Fixed: FixedField{
 				Size: 1,
 			},
 		},
 	},
+	Condition: &Condition{
+		FRN:  10,
+		Mask: 0x80,
+		Variants: map[uint8][]DataField{
+			0x00: Cat4TestPlot,
+			0x80: Cat4TestTrack,
+		},
+	},
 }
 
 var Cat4TestTrack = []DataField{
@@ -17,14 +17,68 @@ const (
 // StandardUAP is User Application Profile
 // Cat is ASTERIX Category number (integer)
 // Version is ASTERIX version for a category
+// Condition declares a conditional UAP (e.g. plot/track), nil if the UAP is not conditional.
+// ReservedExpansion and SpecialPurpose are optional sub-profiles of the RE and SP fields, they are compound-style
+// definitions: the content of the field is an items indicator (FSPEC) followed by the subfields.
 type StandardUAP struct {
-	Name     string
-	Category uint8
-	Version  float64
-	Items    []DataField
+	Name              string
+	Category          uint8
+	Version           float64
+	Items             []DataField
+	Condition         *Condition
+	ReservedExpansion []DataField
+	SpecialPurpose    []DataField
+}
+
+// Condition declares a conditional UAP: the items following a discriminating data item depend on its value.
+// FRN is the discriminating data item, it is the last item of Items.
+// Mask selects the discriminating bits of the octet number Octet (0 is the first octet of the data item).
+// Variants contains the alternative lists of items following FRN, keyed by the masked value.
+type Condition struct {
+	FRN      uint8
+	Octet    uint8
+	Mask     uint8
+	Variants map[uint8][]DataField
+}
+
+// Select returns the items following the discriminating data item, data is the content of this data item.
+// It returns false if data is too short or if no variant is defined for its value.
+func (c *Condition) Select(data []byte) ([]DataField, bool) {
+	if int(c.Octet) >= len(data) {
+		return nil, false
+	}
+	items, found := c.Variants[data[c.Octet]&c.Mask]
+	return items, found
+}
+
+// EffectiveCondition returns the Condition of the UAP. A UAP without Condition whose discriminating item is marked
+// Conditional (deprecated) gets the Condition of the profile of its category in DefaultProfiles when it is
+// discriminated by the same item, e.g. the plot and track variants of Cat001V12. Otherwise its Condition has
+// no variant: the decoding of the records containing the discriminating item fails.
+// It returns nil if the UAP is not conditional.
+func (s StandardUAP) EffectiveCondition() *Condition {
+	if s.Condition != nil {
+		return s.Condition
+	}
+	for _, item := range s.Items {
+		if !item.Conditional {
+			continue
+		}
+		if c := DefaultProfiles[s.Category].Condition; c != nil && c.FRN == item.FRN {
+			return c
+		}
+		return &Condition{FRN: item.FRN}
+	}
+	return nil
 }
 
 // DataField describes FRN(Field Reference Number)
+// Compound is the list of subfields of a Compound field, or the sub-profile of a RE or SP field.
+// SubFields is the optional bit-level definition of the content of the field.
+// Conditional marks the discriminating data item of a conditional UAP.
+//
+// Deprecated: Conditional is kept for the profiles written before StandardUAP.Condition, use Condition instead.
+// See StandardUAP.EffectiveCondition.
 type DataField struct {
 	FRN         uint8
 	DataItem    string
@@ -35,8 +89,40 @@ type DataField struct {
 	Repetitive  RepetitiveField
 	Explicit    ExplicitField
 	Compound    []DataField
+	SubFields   []SubField
 	Conditional bool
 }
+
+// Format is the representation of the value of a SubField.
+type Format uint8
+
+const (
+	Numeric Format = iota // integer, or float64 when scaled
+	Octal                 // string of octal digits, e.g. Mode-3/A code
+	Hex                   // string of hexadecimal digits, e.g. aircraft address
+	ICAO6                 // string of 6-bit characters (ICAO Annex 10), e.g. aircraft identification
+	ASCII                 // string of 8-bit characters
+)
+
+// SubField describes a value of a DataField at the bit level.
+// The bits are numbered as in the ASTERIX specifications: bit 1 is the least significant bit of the last octet.
+// From is the most significant bit and To the least significant bit of the value (From >= To, 64 bits max).
+// Part is the part of an Extended field: 0 for the primary part, n for the nth extension.
+// The bits of a Repetitive field are numbered in one repetition, those of an Explicit field without its length.
+// Signed is true for a two's complement value, Scale is the value of the LSB (0 for an integer) in Unit.
+// Enum contains the meaning of the values of an enumeration.
+type SubField struct {
+	Name   string
+	From   uint8
+	To     uint8
+	Part   uint8
+	Signed bool
+	Scale  float64
+	Unit   string
+	Enum   map[uint64]string
+	Format Format
+}
+
 type FixedField struct {
 	Size uint8
 }
@@ -48,6 +134,7 @@ type RepetitiveField struct {
 	SubItemSize uint8
 }
 type ExplicitField struct {
+	Size uint8 // total length of the field (length indicator included) when it is defined, 0 if variable
 }
 
 /*
@@ -0,0 +1,6 @@
+package uap
+
+// The profiles below are generated by cmd/uapgen from the specification files of the specs directory,
+// TestGeneratedProfiles (cmd/uapgen) checks that they are up to date.
+
+//go:generate go run ../cmd/uapgen -spec specs/cat034_1.27.json -var Cat034V127 -o cat034.go
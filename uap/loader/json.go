@@ -0,0 +1,607 @@
+package loader
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/mokhtarimokhtar/goasterix/uap"
+)
+
+// jsonCategory is a category of the asterix-specs JSON format.
+type jsonCategory struct {
+	Number  uint8 `json:"number"`
+	Title   string
+	Edition struct {
+		Major int
+		Minor int
+	}
+	Catalogue []*jsonItem
+	UAP       jsonUAP
+}
+
+// jsonItem is an item of the catalogue or a subitem of a variation, nil is a spare position of a list.
+// A spare item is either {"spare": true, "length": n} or {"type": "Spare", "length": n}.
+type jsonItem struct {
+	Spare     bool
+	Type      string
+	Length    int
+	Name      string
+	Title     string
+	Variation *jsonVariation
+}
+
+func (it *jsonItem) isSpare() bool {
+	return it == nil || it.Spare || it.Type == "Spare"
+}
+
+// jsonVariation is the structure of an item: Element, Group, Extended, Repetitive, Explicit or Compound.
+type jsonVariation struct {
+	Type      string
+	Size      int
+	Rule      *jsonRule
+	Content   json.RawMessage
+	Items     []*jsonItem
+	First     int
+	Extents   int
+	Rep       *jsonRep
+	Variation *jsonVariation
+	Expl      *string
+}
+
+type jsonRep struct {
+	Type string
+	Size int
+}
+
+// jsonRule is the content rule of an Element, the default content of a dependent rule is used.
+type jsonRule struct {
+	Type    string
+	Value   json.RawMessage
+	Default json.RawMessage
+}
+
+// jsonContent is the meaning of the bits of an Element: Raw, Table, String, Integer, Quantity or Bds.
+type jsonContent struct {
+	Type      string
+	Signed    bool
+	Lsb       json.RawMessage
+	Scaling   json.RawMessage
+	Unit      string
+	Values    [][]interface{}
+	Variation string
+}
+
+// jsonNumber is a number expression: a JSON number or an object Integer, Real, Div, Pow or Mul.
+type jsonNumber struct {
+	Type        string
+	Value       float64
+	Numerator   json.RawMessage
+	Denominator json.RawMessage
+	Base        float64
+	Exponent    float64
+	A           json.RawMessage
+	B           json.RawMessage
+}
+
+type jsonUAP struct {
+	Type       string
+	Items      []*string
+	Variations []struct {
+		Name  string
+		Items []*string
+	}
+	Selector *struct {
+		Item  json.RawMessage
+		Rules [][]interface{}
+	}
+}
+
+// LoadJSON builds a User Application Profile from a category of the asterix-specs JSON format.
+// A category with several UAPs (e.g. CAT001 plot/track) becomes a conditional UAP.
+func LoadJSON(data []byte) (uap.StandardUAP, error) {
+	var cat jsonCategory
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if err := dec.Decode(&cat); err != nil {
+		return uap.StandardUAP{}, fmt.Errorf("%w: %v", ErrSpecInvalid, err)
+	}
+	l := jsonLoader{category: cat.Number, catalogue: make(map[string]*jsonItem)}
+	for _, item := range cat.Catalogue {
+		if item != nil {
+			l.catalogue[item.Name] = item
+		}
+	}
+
+	version := fmt.Sprintf("%d.%d", cat.Edition.Major, cat.Edition.Minor)
+	stdUAP := uap.StandardUAP{
+		Name:     fmt.Sprintf("cat%03d_%s", cat.Number, version),
+		Category: cat.Number,
+	}
+	stdUAP.Version, _ = strconv.ParseFloat(version, 64)
+
+	var err error
+	switch cat.UAP.Type {
+	case "uap", "":
+		stdUAP.Items, err = l.uapItems(cat.UAP.Items, 0)
+	case "uaps":
+		err = l.conditional(&stdUAP, cat.UAP)
+	default:
+		err = fmt.Errorf("%w: uap type %s", ErrSpecInvalid, cat.UAP.Type)
+	}
+	return stdUAP, err
+}
+
+type jsonLoader struct {
+	category  uint8
+	catalogue map[string]*jsonItem
+}
+
+// uapItems returns the data fields of a list of UAP items, the first FRN is offset+1.
+func (l jsonLoader) uapItems(names []*string, offset int) ([]uap.DataField, error) {
+	fields := make([]uap.DataField, 0, len(names))
+	for i, name := range names {
+		frn := uint8(offset + i + 1)
+		if name == nil || *name == "-" {
+			fields = append(fields, uap.DataField{FRN: frn, Type: uap.Spare})
+			continue
+		}
+		item, found := l.catalogue[*name]
+		if !found {
+			return nil, fmt.Errorf("%w: item %s not in catalogue", ErrSpecInvalid, *name)
+		}
+		field, err := l.dataField(item, frn, fmt.Sprintf("I%03d/%s", l.category, item.Name))
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// conditional declares the UAP variations (type "uaps") as a conditional UAP:
+// the variations share the items until the selector item, the value of the selector selects the following items.
+func (l jsonLoader) conditional(stdUAP *uap.StandardUAP, u jsonUAP) error {
+	if u.Selector == nil || len(u.Variations) == 0 {
+		return fmt.Errorf("%w: uaps without selector", ErrSpecInvalid)
+	}
+	var path []string
+	if err := json.Unmarshal(u.Selector.Item, &path); err != nil {
+		var name string
+		if err := json.Unmarshal(u.Selector.Item, &name); err != nil {
+			return fmt.Errorf("%w: selector %s", ErrSpecInvalid, u.Selector.Item)
+		}
+		path = []string{name}
+	}
+	if len(path) == 0 {
+		return fmt.Errorf("%w: selector without item", ErrSpecInvalid)
+	}
+
+	// the selector item must be at the same place in every variation, with the same items before it
+	first := u.Variations[0].Items
+	index := -1
+	for i, name := range first {
+		if name != nil && *name == path[0] {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("%w: selector %s not in uap", ErrSpecInvalid, path[0])
+	}
+	for _, v := range u.Variations {
+		if len(v.Items) <= index {
+			return fmt.Errorf("%w: selector %s not in uap %s", ErrSpecInvalid, path[0], v.Name)
+		}
+		for i := 0; i <= index; i++ {
+			if (v.Items[i] == nil) != (first[i] == nil) || (first[i] != nil && *v.Items[i] != *first[i]) {
+				return fmt.Errorf("%w: uap %s does not share the items before the selector", ErrSpecInvalid, v.Name)
+			}
+		}
+	}
+
+	var err error
+	stdUAP.Items, err = l.uapItems(first[:index+1], 0)
+	if err != nil {
+		return err
+	}
+	selector := stdUAP.Items[index]
+	octet, mask, shift, err := selectorBits(selector, path)
+	if err != nil {
+		return err
+	}
+
+	variants := make(map[string][]uap.DataField, len(u.Variations))
+	for _, v := range u.Variations {
+		variants[v.Name], err = l.uapItems(v.Items[index+1:], index+1)
+		if err != nil {
+			return err
+		}
+	}
+	cond := &uap.Condition{
+		FRN:      uint8(index + 1),
+		Octet:    octet,
+		Mask:     mask,
+		Variants: make(map[uint8][]uap.DataField, len(u.Selector.Rules)),
+	}
+	for _, rule := range u.Selector.Rules {
+		if len(rule) != 2 {
+			return fmt.Errorf("%w: selector rule %v", ErrSpecInvalid, rule)
+		}
+		value, ok1 := rule[0].(float64)
+		name, ok2 := rule[1].(string)
+		if !ok1 || !ok2 {
+			return fmt.Errorf("%w: selector rule %v", ErrSpecInvalid, rule)
+		}
+		variant, found := variants[name]
+		if !found {
+			return fmt.Errorf("%w: selector rule %v", ErrSpecInvalid, rule)
+		}
+		cond.Variants[uint8(value)<<shift] = variant
+	}
+	stdUAP.Condition = cond
+	return nil
+}
+
+// selectorBits returns the position of the selector subfield in its item: octet, mask and shift of the value.
+// The selector must be contained in one octet of a Fixed or Extended item.
+func selectorBits(field uap.DataField, path []string) (uint8, uint8, uint8, error) {
+	var sf *uap.SubField
+	for i := range field.SubFields {
+		if len(path) == 1 || field.SubFields[i].Name == path[len(path)-1] {
+			sf = &field.SubFields[i]
+			break
+		}
+	}
+	var size int
+	switch field.Type {
+	case uap.Fixed:
+		size = int(field.Fixed.Size)
+	case uap.Extended:
+		size = int(field.Extended.PrimarySize)
+		if sf != nil && sf.Part != 0 {
+			size = int(field.Extended.SecondarySize)
+		}
+	}
+	if sf == nil || size == 0 || (sf.From-1)/8 != (sf.To-1)/8 {
+		return 0, 0, 0, fmt.Errorf("%w: selector %v", ErrSpecInvalid, path)
+	}
+
+	octet := size - 1 - int(sf.To-1)/8
+	if sf.Part != 0 {
+		octet += int(field.Extended.PrimarySize) + (int(sf.Part)-1)*size
+	}
+	shift := (sf.To - 1) % 8
+	width := sf.From - sf.To + 1
+	mask := uint8(0xff>>(8-width)) << shift
+	return uint8(octet), mask, shift, nil
+}
+
+// dataField returns the data field of an item of the UAP or of a Compound item.
+func (l jsonLoader) dataField(item *jsonItem, frn uint8, dataItem string) (uap.DataField, error) {
+	field := uap.DataField{
+		FRN:         frn,
+		DataItem:    dataItem,
+		Description: item.Title,
+	}
+	v := item.Variation
+	if v == nil {
+		return field, fmt.Errorf("%w: %s without variation", ErrSpecInvalid, dataItem)
+	}
+
+	switch v.Type {
+	case "Element", "Group":
+		size, err := bitSize(v)
+		if err != nil || size%8 != 0 {
+			return field, fmt.Errorf("%w: %s size", ErrSpecInvalid, dataItem)
+		}
+		field.Type = uap.Fixed
+		field.Fixed.Size = uint8(size / 8)
+		_, err = subFields(v, item.Name, size, 0, &field.SubFields)
+		return field, err
+
+	case "Extended":
+		parts, err := extendedParts(v)
+		if err != nil {
+			return field, fmt.Errorf("%w: %s", err, dataItem)
+		}
+		field.Type = uap.Extended
+		field.Extended.PrimarySize = uint8(parts[0].size / 8)
+		field.Extended.SecondarySize = field.Extended.PrimarySize
+		if len(parts) > 1 {
+			field.Extended.SecondarySize = uint8(parts[1].size / 8)
+		}
+		for i, p := range parts {
+			from := p.size
+			for _, it := range p.items {
+				size, err := itemSize(it)
+				if err != nil {
+					return field, err
+				}
+				if !it.isSpare() {
+					if _, err := subFields(it.Variation, it.Name, from, uint8(i), &field.SubFields); err != nil {
+						return field, err
+					}
+				}
+				from -= size
+			}
+		}
+		return field, nil
+
+	case "Repetitive":
+		if v.Variation == nil {
+			return field, fmt.Errorf("%w: %s without variation", ErrSpecInvalid, dataItem)
+		}
+		size, err := bitSize(v.Variation)
+		if err != nil {
+			return field, err
+		}
+		if v.Rep != nil && v.Rep.Type == "Fx" {
+			// each repetition ends with a FX bit: it is an Extended item with parts of the same size
+			if (size+1)%8 != 0 {
+				return field, fmt.Errorf("%w: %s size", ErrSpecInvalid, dataItem)
+			}
+			field.Type = uap.Extended
+			field.Extended.PrimarySize = uint8((size + 1) / 8)
+			field.Extended.SecondarySize = field.Extended.PrimarySize
+			_, err = subFields(v.Variation, item.Name, size+1, 0, &field.SubFields)
+			return field, err
+		}
+		if (v.Rep != nil && v.Rep.Size != 0 && v.Rep.Size != 8) || size%8 != 0 {
+			return field, fmt.Errorf("%w: %s size", ErrSpecInvalid, dataItem)
+		}
+		field.Type = uap.Repetitive
+		field.Repetitive.SubItemSize = uint8(size / 8)
+		_, err = subFields(v.Variation, item.Name, size, 0, &field.SubFields)
+		return field, err
+
+	case "Explicit":
+		field.Type = uap.Explicit
+		switch {
+		case item.Name == "SP" || (v.Expl != nil && *v.Expl == "SpecialPurpose"):
+			field.Type = uap.SP
+			field.DataItem = "SP-Data Item"
+		case item.Name == "RE" || (v.Expl != nil && *v.Expl == "ReservedExpansion"):
+			field.Type = uap.RE
+			field.DataItem = "RE-Data Item"
+		case v.Variation != nil:
+			// the size of the content is defined by a fixed variation, with the length indicator
+			size, err := bitSize(v.Variation)
+			if err != nil || size%8 != 0 || size/8 >= 0xff {
+				return field, fmt.Errorf("%w: %s size", ErrSpecInvalid, dataItem)
+			}
+			field.Explicit.Size = uint8(size/8 + 1)
+			_, err = subFields(v.Variation, item.Name, size, 0, &field.SubFields)
+			return field, err
+		}
+		return field, nil
+
+	case "Compound":
+		field.Type = uap.Compound
+		for i, it := range v.Items {
+			subFRN := uint8(i + 1)
+			if it.isSpare() {
+				field.Compound = append(field.Compound, uap.DataField{FRN: subFRN, Type: uap.Spare})
+				continue
+			}
+			sub, err := l.dataField(it, subFRN, it.Name)
+			if err != nil {
+				return field, err
+			}
+			field.Compound = append(field.Compound, sub)
+		}
+		return field, nil
+	}
+	return field, fmt.Errorf("%w: %s variation %s", ErrSpecInvalid, dataItem, v.Type)
+}
+
+// extendedPart is a part of an Extended item: its items and its size in bits with the FX bit.
+type extendedPart struct {
+	items []*jsonItem
+	size  int
+}
+
+// extendedParts splits the items of an Extended variation into parts.
+// The parts are delimited by the FX bits (nil items), or by the sizes first and extents of the older format.
+func extendedParts(v *jsonVariation) ([]extendedPart, error) {
+	var parts []extendedPart
+	var p extendedPart
+	limit := v.First - 1
+	for _, it := range v.Items {
+		if v.First == 0 && it == nil {
+			p.size++ // FX
+			parts = append(parts, p)
+			p = extendedPart{}
+			continue
+		}
+		size, err := itemSize(it)
+		if err != nil {
+			return nil, err
+		}
+		p.items = append(p.items, it)
+		p.size += size
+		if v.First != 0 && p.size == limit {
+			p.size++ // FX
+			parts = append(parts, p)
+			p = extendedPart{}
+			limit = v.Extents - 1
+		}
+	}
+	if len(p.items) != 0 {
+		parts = append(parts, p)
+	}
+	if len(parts) == 0 {
+		return nil, ErrSpecInvalid
+	}
+	for _, p := range parts {
+		if p.size%8 != 0 {
+			return nil, ErrSpecInvalid
+		}
+	}
+	return parts, nil
+}
+
+// itemSize returns the size in bits of an item of a Group or Extended variation.
+func itemSize(it *jsonItem) (int, error) {
+	if it == nil {
+		return 0, ErrSpecInvalid
+	}
+	if it.isSpare() {
+		return it.Length, nil
+	}
+	if it.Variation == nil {
+		return 0, ErrSpecInvalid
+	}
+	return bitSize(it.Variation)
+}
+
+// bitSize returns the size in bits of an Element or Group variation.
+func bitSize(v *jsonVariation) (int, error) {
+	switch v.Type {
+	case "Element":
+		return v.Size, nil
+	case "Group":
+		total := 0
+		for _, it := range v.Items {
+			size, err := itemSize(it)
+			if err != nil {
+				return 0, err
+			}
+			total += size
+		}
+		return total, nil
+	}
+	return 0, fmt.Errorf("%w: variation %s in a fixed part", ErrSpecInvalid, v.Type)
+}
+
+// subFields appends the subfields of an Element or Group variation beginning at bit from,
+// it returns the size in bits of the variation.
+// The subfields wider than 64 bits are not declared.
+func subFields(v *jsonVariation, name string, from int, part uint8, out *[]uap.SubField) (int, error) {
+	switch v.Type {
+	case "Element":
+		if v.Size > 0 && v.Size <= 64 && from-v.Size >= 0 {
+			sf := uap.SubField{Name: name, From: uint8(from), To: uint8(from - v.Size + 1), Part: part}
+			if err := elementContent(v, &sf); err != nil {
+				return 0, err
+			}
+			*out = append(*out, sf)
+		}
+		return v.Size, nil
+
+	case "Group":
+		total := 0
+		for _, it := range v.Items {
+			size, err := itemSize(it)
+			if err != nil {
+				return 0, err
+			}
+			if !it.isSpare() {
+				if _, err := subFields(it.Variation, it.Name, from-total, part, out); err != nil {
+					return 0, err
+				}
+			}
+			total += size
+		}
+		return total, nil
+	}
+	return 0, fmt.Errorf("%w: variation %s in a fixed part", ErrSpecInvalid, v.Type)
+}
+
+// elementContent sets the representation of a subfield from the content of its Element.
+func elementContent(v *jsonVariation, sf *uap.SubField) error {
+	raw := v.Content
+	if v.Rule != nil {
+		raw = v.Rule.Value
+		if len(raw) == 0 {
+			raw = v.Rule.Default
+		}
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	var c jsonContent
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return fmt.Errorf("%w: content of %s", ErrSpecInvalid, sf.Name)
+	}
+
+	switch c.Type {
+	case "Table":
+		sf.Enum = make(map[uint64]string, len(c.Values))
+		for _, kv := range c.Values {
+			if len(kv) != 2 {
+				return fmt.Errorf("%w: table of %s", ErrSpecInvalid, sf.Name)
+			}
+			key, ok1 := kv[0].(float64)
+			meaning, ok2 := kv[1].(string)
+			if !ok1 || !ok2 {
+				return fmt.Errorf("%w: table of %s", ErrSpecInvalid, sf.Name)
+			}
+			sf.Enum[uint64(key)] = meaning
+		}
+	case "String":
+		switch c.Variation {
+		case "StringAscii":
+			sf.Format = uap.ASCII
+		case "StringICAO":
+			sf.Format = uap.ICAO6
+		case "StringOctal":
+			sf.Format = uap.Octal
+		}
+	case "Integer":
+		sf.Signed = c.Signed
+	case "Quantity":
+		sf.Signed = c.Signed
+		sf.Unit = c.Unit
+		lsb := c.Lsb
+		if len(lsb) == 0 {
+			lsb = c.Scaling
+		}
+		scale, err := number(lsb)
+		if err != nil {
+			return fmt.Errorf("%w: lsb of %s", ErrSpecInvalid, sf.Name)
+		}
+		sf.Scale = scale
+	}
+	return nil
+}
+
+// number evaluates a number expression.
+func number(raw json.RawMessage) (float64, error) {
+	var f float64
+	if err := json.Unmarshal(raw, &f); err == nil {
+		return f, nil
+	}
+	var n jsonNumber
+	if err := json.Unmarshal(raw, &n); err != nil {
+		return 0, err
+	}
+	switch n.Type {
+	case "Integer", "Real":
+		return n.Value, nil
+	case "Pow":
+		return math.Pow(n.Base, n.Exponent), nil
+	case "Div", "Mul":
+		a, b := n.Numerator, n.Denominator
+		if n.Type == "Mul" {
+			a, b = n.A, n.B
+		}
+		x, err := number(a)
+		if err != nil {
+			return 0, err
+		}
+		y, err := number(b)
+		if err != nil {
+			return 0, err
+		}
+		if n.Type == "Mul" {
+			return x * y, nil
+		}
+		if y == 0 {
+			return 0, ErrSpecInvalid
+		}
+		return x / y, nil
+	}
+	return 0, ErrSpecInvalid
+}
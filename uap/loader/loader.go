@@ -0,0 +1,93 @@
+// Package loader builds User Application Profiles (uap.StandardUAP) at runtime from specification files:
+// the asterix-specs JSON format (https://github.com/zoranbosnjak/asterix-specs)
+// and the XML format of the asterix decoder tool and Wireshark (asterix.dtd).
+//
+// A loaded profile is registered in a Decoder like a compiled-in one:
+//
+//	stdUAP, err := loader.LoadFile("cat048_1.31.json")
+//	if err != nil { ... }
+//	decoder := goasterix.NewDecoder(goasterix.WithProfile(stdUAP))
+package loader
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/mokhtarimokhtar/goasterix"
+	"github.com/mokhtarimokhtar/goasterix/uap"
+)
+
+var (
+	// ErrFormatUnknown reports that a specification is neither in JSON nor in XML format.
+	ErrFormatUnknown = errors.New("[ASTERIX] specification format unknown")
+
+	// ErrSpecInvalid reports that a specification can not be converted into a User Application Profile.
+	ErrSpecInvalid = errors.New("[ASTERIX] specification invalid or not supported")
+)
+
+// Load builds a User Application Profile from a specification, its format (JSON or XML) is detected from its content.
+func Load(data []byte) (uap.StandardUAP, error) {
+	tmp := bytes.TrimSpace(data)
+	switch {
+	case len(tmp) != 0 && tmp[0] == '{':
+		return LoadJSON(tmp)
+	case len(tmp) != 0 && tmp[0] == '<':
+		return LoadXML(tmp)
+	}
+	return uap.StandardUAP{}, ErrFormatUnknown
+}
+
+// LoadFile builds a User Application Profile from a specification file.
+func LoadFile(path string) (uap.StandardUAP, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return uap.StandardUAP{}, err
+	}
+	return Load(data)
+}
+
+// LoadDir builds the User Application Profiles of all the specification files (*.json, *.xml) of a directory,
+// sorted by file name.
+func LoadDir(dir string) ([]uap.StandardUAP, error) {
+	var paths []string
+	for _, pattern := range []string{"*.json", "*.xml"} {
+		tmp, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, tmp...)
+	}
+	sort.Strings(paths)
+
+	profiles := make([]uap.StandardUAP, 0, len(paths))
+	for _, path := range paths {
+		stdUAP, err := LoadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, stdUAP)
+	}
+	return profiles, nil
+}
+
+// WithFiles loads specification files and returns the DecoderOption registering their profiles,
+// a profile replaces the one of its category (the last file wins).
+// e.g. option, err := loader.WithFiles("cat048.json"); decoder := goasterix.NewDecoder(option)
+func WithFiles(paths ...string) (goasterix.DecoderOption, error) {
+	profiles := make([]uap.StandardUAP, 0, len(paths))
+	for _, path := range paths {
+		stdUAP, err := LoadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, stdUAP)
+	}
+	return func(d *goasterix.Decoder) {
+		for _, stdUAP := range profiles {
+			goasterix.WithProfile(stdUAP)(d)
+		}
+	}, nil
+}
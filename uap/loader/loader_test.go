@@ -0,0 +1,249 @@
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mokhtarimokhtar/goasterix"
+	"github.com/mokhtarimokhtar/goasterix/generic"
+	"github.com/mokhtarimokhtar/goasterix/uap"
+	"github.com/mokhtarimokhtar/goasterix/util"
+)
+
+func TestLoadFile(t *testing.T) {
+	// setup
+	types := []uap.TypeField{uap.Fixed, uap.Fixed, uap.Extended, uap.Fixed, uap.Fixed, uap.Compound, uap.Repetitive,
+		uap.Spare, uap.SP}
+	compoundTypes := []uap.TypeField{uap.Fixed, uap.Spare, uap.Fixed}
+	dataSet := []string{"testdata/cat048.json", "testdata/cat048.xml"}
+
+	for _, path := range dataSet {
+		// Act
+		stdUAP, err := LoadFile(path)
+
+		// Assert
+		if err != nil {
+			t.Errorf("FAIL: %s - error = %v; Expected: %v", path, err, nil)
+			continue
+		}
+		if stdUAP.Category != 48 || stdUAP.Version != 1.27 || stdUAP.Name != "cat048_1.27" {
+			t.Errorf("FAIL: %s - profile = %v, %v, %v; Expected: %v, %v, %v", path,
+				stdUAP.Name, stdUAP.Category, stdUAP.Version, "cat048_1.27", 48, 1.27)
+		}
+		if len(stdUAP.Items) != len(types) {
+			t.Fatalf("FAIL: %s - nbOfItems = %v; Expected: %v", path, len(stdUAP.Items), len(types))
+		}
+		for i, field := range stdUAP.Items {
+			if field.FRN != uint8(i+1) || field.Type != types[i] {
+				t.Errorf("FAIL: %s - FRN = %v, type = %v; Expected: %v, %v", path, field.FRN, field.Type, i+1, types[i])
+			}
+		}
+		if stdUAP.Items[1].Fixed.Size != 3 || stdUAP.Items[2].Extended != (uap.ExtendedField{PrimarySize: 1, SecondarySize: 1}) ||
+			stdUAP.Items[6].Repetitive.SubItemSize != 8 {
+			t.Errorf("FAIL: %s - sizes = %v, %v, %v; Expected: %v, %v, %v", path, stdUAP.Items[1].Fixed,
+				stdUAP.Items[2].Extended, stdUAP.Items[6].Repetitive, 3, "{1 1}", 8)
+		}
+		compound := stdUAP.Items[5].Compound
+		if len(compound) != len(compoundTypes) {
+			t.Fatalf("FAIL: %s - nbOfSubfields = %v; Expected: %v", path, len(compound), len(compoundTypes))
+		}
+		for i, field := range compound {
+			if field.FRN != uint8(i+1) || field.Type != compoundTypes[i] {
+				t.Errorf("FAIL: %s - FRN = %v, type = %v; Expected: %v, %v", path, field.FRN, field.Type, i+1, compoundTypes[i])
+			}
+		}
+		t.Logf("SUCCESS: %s - profile = %v", path, stdUAP.Name)
+	}
+}
+
+func TestWithFiles_Decode(t *testing.T) {
+	// setup
+	input := "30 0020 ff40 0836 429b52 a180 94c70181 0913 a0 10b7 01 0102030405060708 02aa"
+	type dataTest struct {
+		TestCaseName string
+		path         string
+	}
+	dataSet := []dataTest{
+		{TestCaseName: "asterix-specs JSON", path: "testdata/cat048.json"},
+		{TestCaseName: "asterix XML", path: "testdata/cat048.xml"},
+	}
+
+	for _, row := range dataSet {
+		// Arrange
+		data, _ := util.HexStringToByte(input)
+		option, err := WithFiles(row.path)
+		if err != nil {
+			t.Fatalf("FAIL: %s - error = %v; Expected: %v", row.TestCaseName, err, nil)
+		}
+		d := goasterix.NewDecoder(option)
+		stdUAP, _ := d.Profile(48)
+
+		// Act
+		db, unRead, err := d.DecodeDataBlock(data)
+
+		// Assert
+		if err != nil || unRead != 0 || len(db.Records) != 1 {
+			t.Fatalf("FAIL: %s - error = %v, unRead = %v; Expected: %v, %v", row.TestCaseName, err, unRead, nil, 0)
+		}
+		fields, err := generic.DecodeRecord(db.Records[0], stdUAP)
+		if err != nil || len(fields) != 8 {
+			t.Fatalf("FAIL: %s - error = %v, nbOfFields = %v; Expected: %v, %v", row.TestCaseName, err, len(fields), nil, 8)
+		}
+		tod, _ := fields[1].Get(fields[1].Values[0].Name)
+		typ, _ := fields[2].Get("TYP")
+		tst, _ := fields[2].Get("TST")
+		rho, _ := fields[3].Get("RHO")
+		sam, _ := fields[5].Items[1].Get("SAM")
+		if tod.Value != 34102.640625 || typ.Raw != 5 || tst.Raw != 1 || rho.Value != 148.77734375 ||
+			fmt.Sprint(sam.Value) != "-73" {
+			t.Errorf("FAIL: %s - values = %v, %v, %v, %v, %v; Expected: %v, %v, %v, %v, %v", row.TestCaseName,
+				tod.Value, typ.Raw, tst.Raw, rho.Value, sam.Value, 34102.640625, 5, 1, 148.77734375, -73)
+		} else {
+			t.Logf("SUCCESS: %s - values = %v, %v, %v, %v, %v", row.TestCaseName, tod.Value, typ.Raw, tst.Raw, rho.Value, sam.Value)
+		}
+	}
+}
+
+func TestLoadJSON_Conditional(t *testing.T) {
+	// Arrange
+	input := `{
+		"number": 1, "edition": {"major": 1, "minor": 2},
+		"catalogue": [
+			{"name": "010", "title": "Data Source Identifier",
+			 "variation": {"type": "Element", "size": 16, "rule": {"type": "ContextFree", "value": {"type": "Raw"}}}},
+			{"name": "020", "title": "Target Report Descriptor",
+			 "variation": {"type": "Extended", "items": [
+				{"type": "Item", "name": "TYP", "variation": {"type": "Element", "size": 1}},
+				{"type": "Spare", "length": 6},
+				null]}},
+			{"name": "040", "title": "Measured Position",
+			 "variation": {"type": "Element", "size": 32}},
+			{"name": "161", "title": "Track Plot Number",
+			 "variation": {"type": "Element", "size": 16}}
+		],
+		"uap": {
+			"type": "uaps",
+			"variations": [
+				{"name": "plot", "items": ["010", "020", "040"]},
+				{"name": "track", "items": ["010", "020", "161", "040"]}
+			],
+			"selector": {"item": ["020", "TYP"], "rules": [[0, "plot"], [1, "track"]]}
+		}
+	}`
+
+	// Act
+	stdUAP, err := LoadJSON([]byte(input))
+
+	// Assert
+	if err != nil {
+		t.Fatalf("FAIL: error = %v; Expected: %v", err, nil)
+	}
+	cond := stdUAP.Condition
+	if cond == nil || cond.FRN != 2 || cond.Octet != 0 || cond.Mask != 0x80 || len(stdUAP.Items) != 2 {
+		t.Fatalf("FAIL: condition = %v; Expected: FRN 2, octet 0, mask 0x80", cond)
+	}
+	plot, track := cond.Variants[0x00], cond.Variants[0x80]
+	if len(plot) != 1 || plot[0].FRN != 3 || plot[0].DataItem != "I001/040" ||
+		len(track) != 2 || track[0].FRN != 3 || track[0].DataItem != "I001/161" {
+		t.Errorf("FAIL: variants = %v; Expected: plot [I001/040], track [I001/161 I001/040]", cond.Variants)
+	} else {
+		t.Logf("SUCCESS: variants = %v", cond.Variants)
+	}
+}
+
+func TestLoad_ExplicitSize(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		input        string
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "JSON",
+			input: `{"number": 48, "edition": {"major": 1, "minor": 0},
+				"catalogue": [{"name": "010", "variation": {"type": "Explicit", "variation": {"type": "Element", "size": 16}}}],
+				"uap": {"type": "uap", "items": ["010"]}}`,
+		},
+		{
+			TestCaseName: "XML",
+			input: `<Category id="48" ver="1.0"><DataItem id="010"><DataItemFormat><Explicit><Fixed length="2">
+				<Bits from="16" to="1"><BitsShortName>VAL</BitsShortName></Bits></Fixed></Explicit></DataItemFormat></DataItem>
+				<UAP><UAPItem frn="1">010</UAPItem></UAP></Category>`,
+		},
+	}
+
+	for _, row := range dataSet {
+		// Arrange
+		stdUAP, err := Load([]byte(row.input))
+		if err != nil {
+			t.Fatalf("FAIL: %s - error = %v; Expected: %v", row.TestCaseName, err, nil)
+		}
+		data, _ := util.HexStringToByte("80 02ff")
+		d := goasterix.NewDecoder(goasterix.WithProfile(stdUAP), goasterix.WithStrict())
+
+		// Act
+		_, _, warnings, err := d.DecodeRecord(48, data)
+
+		// Assert
+		if size := stdUAP.Items[0].Explicit.Size; size != 3 {
+			t.Errorf("FAIL: %s - size = %v; Expected: %v", row.TestCaseName, size, 3)
+		}
+		if err != nil || len(warnings) != 1 || warnings[0].Kind != goasterix.WarnExplicitSize {
+			t.Errorf("FAIL: %s - error = %v, warnings = %v; Expected: %v, %v", row.TestCaseName, err, warnings, nil,
+				goasterix.WarnExplicitSize)
+		} else {
+			t.Logf("SUCCESS: %s - warnings = %v", row.TestCaseName, warnings)
+		}
+	}
+}
+
+func TestLoad_Error(t *testing.T) {
+	// setup
+	type dataTest struct {
+		TestCaseName string
+		input        string
+		err          error
+	}
+	dataSet := []dataTest{
+		{
+			TestCaseName: "format unknown",
+			input:        "cat048",
+			err:          ErrFormatUnknown,
+		},
+		{
+			TestCaseName: "JSON item not in catalogue",
+			input:        `{"number": 48, "catalogue": [], "uap": {"type": "uap", "items": ["010"]}}`,
+			err:          ErrSpecInvalid,
+		},
+		{
+			TestCaseName: "JSON size not multiple of octet",
+			input: `{"number": 48, "catalogue": [{"name": "010", "variation": {"type": "Element", "size": 12}}],
+				"uap": {"type": "uap", "items": ["010"]}}`,
+			err: ErrSpecInvalid,
+		},
+		{
+			TestCaseName: "XML without UAP",
+			input:        `<Category id="48" ver="1.27"></Category>`,
+			err:          ErrSpecInvalid,
+		},
+		{
+			TestCaseName: "XML format unknown",
+			input: `<Category id="48" ver="1.27"><DataItem id="010"><DataItemFormat><Unknown/></DataItemFormat></DataItem>
+				<UAP><UAPItem frn="1">010</UAPItem></UAP></Category>`,
+			err: ErrSpecInvalid,
+		},
+	}
+
+	for _, row := range dataSet {
+		// Act
+		_, err := Load([]byte(row.input))
+
+		// Assert
+		if !errors.Is(err, row.err) {
+			t.Errorf("FAIL: %s - error = %v; Expected: %v", row.TestCaseName, err, row.err)
+		} else {
+			t.Logf("SUCCESS: error = %v; Expected: %v", err, row.err)
+		}
+	}
+}
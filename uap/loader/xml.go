@@ -0,0 +1,334 @@
+package loader
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mokhtarimokhtar/goasterix/uap"
+)
+
+// xmlNode is an element of the XML format, the order of the children is kept: it is meaningful in a Compound.
+type xmlNode struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Nodes   []xmlNode  `xml:",any"`
+	Text    string     `xml:",chardata"`
+}
+
+func (n *xmlNode) attr(name string) string {
+	for _, a := range n.Attrs {
+		if a.Name.Local == name {
+			return strings.TrimSpace(a.Value)
+		}
+	}
+	return ""
+}
+
+func (n *xmlNode) child(name string) *xmlNode {
+	for i := range n.Nodes {
+		if n.Nodes[i].XMLName.Local == name {
+			return &n.Nodes[i]
+		}
+	}
+	return nil
+}
+
+func (n *xmlNode) children(name string) []*xmlNode {
+	var nodes []*xmlNode
+	for i := range n.Nodes {
+		if n.Nodes[i].XMLName.Local == name {
+			nodes = append(nodes, &n.Nodes[i])
+		}
+	}
+	return nodes
+}
+
+func (n *xmlNode) text(name string) string {
+	if c := n.child(name); c != nil {
+		return strings.TrimSpace(c.Text)
+	}
+	return ""
+}
+
+// LoadXML builds a User Application Profile from a category of the XML format of the asterix decoder tool:
+// Category, DataItem, DataItemFormat (Fixed, Variable, Repetitive, Explicit, Compound, BDS) and UAP.
+// Only the first UAP of a category is used.
+func LoadXML(data []byte) (uap.StandardUAP, error) {
+	var root xmlNode
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = false
+	if err := dec.Decode(&root); err != nil {
+		return uap.StandardUAP{}, fmt.Errorf("%w: %v", ErrSpecInvalid, err)
+	}
+	if root.XMLName.Local != "Category" {
+		return uap.StandardUAP{}, fmt.Errorf("%w: root element %s", ErrSpecInvalid, root.XMLName.Local)
+	}
+	cat, err := strconv.ParseUint(root.attr("id"), 10, 8)
+	if err != nil {
+		return uap.StandardUAP{}, fmt.Errorf("%w: category id %s", ErrSpecInvalid, root.attr("id"))
+	}
+	stdUAP := uap.StandardUAP{
+		Name:     fmt.Sprintf("cat%03d_%s", cat, root.attr("ver")),
+		Category: uint8(cat),
+	}
+	stdUAP.Version, _ = strconv.ParseFloat(root.attr("ver"), 64)
+
+	catalogue := make(map[string]*xmlNode)
+	for _, item := range root.children("DataItem") {
+		catalogue[item.attr("id")] = item
+	}
+
+	u := root.child("UAP")
+	if u == nil {
+		return stdUAP, fmt.Errorf("%w: category without UAP", ErrSpecInvalid)
+	}
+	for _, uapItem := range u.children("UAPItem") {
+		if strings.EqualFold(uapItem.attr("frn"), "FX") {
+			continue
+		}
+		frn, err := strconv.ParseUint(uapItem.attr("frn"), 10, 8)
+		if err != nil || int(frn) != len(stdUAP.Items)+1 {
+			return stdUAP, fmt.Errorf("%w: UAP item frn %s", ErrSpecInvalid, uapItem.attr("frn"))
+		}
+		id := strings.TrimSpace(uapItem.Text)
+		if id == "-" || id == "" {
+			stdUAP.Items = append(stdUAP.Items, uap.DataField{FRN: uint8(frn), Type: uap.Spare})
+			continue
+		}
+		item, found := catalogue[id]
+		if !found {
+			return stdUAP, fmt.Errorf("%w: item %s not in catalogue", ErrSpecInvalid, id)
+		}
+		field, err := xmlDataField(item, uint8(frn), uint8(cat))
+		if err != nil {
+			return stdUAP, err
+		}
+		stdUAP.Items = append(stdUAP.Items, field)
+	}
+	return stdUAP, nil
+}
+
+// xmlDataField returns the data field of a DataItem of the UAP.
+func xmlDataField(item *xmlNode, frn uint8, cat uint8) (uap.DataField, error) {
+	id := item.attr("id")
+	field := uap.DataField{
+		FRN:         frn,
+		DataItem:    fmt.Sprintf("I%03d/%s", cat, id),
+		Description: item.text("DataItemName"),
+	}
+	format := item.child("DataItemFormat")
+	if format == nil || len(format.Nodes) == 0 {
+		return field, fmt.Errorf("%w: %s without format", ErrSpecInvalid, field.DataItem)
+	}
+	if err := xmlFormat(&format.Nodes[0], &field); err != nil {
+		return field, err
+	}
+
+	switch {
+	case field.Type == uap.Explicit && id == "SP":
+		field.Type = uap.SP
+		field.DataItem = "SP-Data Item"
+		field.Explicit = uap.ExplicitField{}
+	case field.Type == uap.Explicit && id == "RE":
+		field.Type = uap.RE
+		field.DataItem = "RE-Data Item"
+		field.Explicit = uap.ExplicitField{}
+	}
+	return field, nil
+}
+
+// xmlFormat sets the type, the size and the subfields of a data field from a format element.
+func xmlFormat(n *xmlNode, field *uap.DataField) error {
+	switch n.XMLName.Local {
+	case "Fixed":
+		size, err := xmlLength(n)
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, field.DataItem)
+		}
+		field.Type = uap.Fixed
+		field.Fixed.Size = size
+		return xmlSubFields(n, 0, &field.SubFields)
+
+	case "BDS":
+		field.Type = uap.Fixed
+		field.Fixed.Size = 8
+		return nil
+
+	case "Variable":
+		parts := n.children("Fixed")
+		if len(parts) == 0 {
+			return fmt.Errorf("%w: %s without part", ErrSpecInvalid, field.DataItem)
+		}
+		field.Type = uap.Extended
+		for i, p := range parts {
+			size, err := xmlLength(p)
+			if err != nil {
+				return fmt.Errorf("%w: %s", err, field.DataItem)
+			}
+			switch i {
+			case 0:
+				field.Extended.PrimarySize = size
+				field.Extended.SecondarySize = size
+			case 1:
+				field.Extended.SecondarySize = size
+			}
+			if err := xmlSubFields(p, uint8(i), &field.SubFields); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case "Repetitive":
+		if len(n.Nodes) == 0 {
+			return fmt.Errorf("%w: %s without repetition", ErrSpecInvalid, field.DataItem)
+		}
+		var rep uap.DataField
+		if err := xmlFormat(&n.Nodes[0], &rep); err != nil || rep.Type != uap.Fixed {
+			return fmt.Errorf("%w: %s repetition", ErrSpecInvalid, field.DataItem)
+		}
+		field.Type = uap.Repetitive
+		field.Repetitive.SubItemSize = rep.Fixed.Size
+		field.SubFields = rep.SubFields
+		return nil
+
+	case "Explicit":
+		field.Type = uap.Explicit
+		if c := n.child("Fixed"); c != nil {
+			// the size of the content is defined by a single Fixed element, with the length indicator
+			if size, err := xmlLength(c); err == nil && len(n.Nodes) == 1 && size < 0xff {
+				field.Explicit.Size = size + 1
+			}
+			return xmlSubFields(c, 0, &field.SubFields)
+		}
+		return nil
+
+	case "Compound":
+		return xmlCompound(n, field)
+	}
+	return fmt.Errorf("%w: %s format %s", ErrSpecInvalid, field.DataItem, n.XMLName.Local)
+}
+
+// xmlCompound sets the data subfields of a Compound: the first element is the primary subfield (Variable),
+// each bit of the primary subfield, except FX and spare bits, corresponds to the next element.
+func xmlCompound(n *xmlNode, field *uap.DataField) error {
+	if len(n.Nodes) == 0 || n.Nodes[0].XMLName.Local != "Variable" {
+		return fmt.Errorf("%w: %s without primary subfield", ErrSpecInvalid, field.DataItem)
+	}
+	type indicator struct {
+		frn  uint8
+		name string
+	}
+	var indicators []indicator
+	for i, p := range n.Nodes[0].children("Fixed") {
+		for _, bits := range p.children("Bits") {
+			bit, err := strconv.ParseUint(bits.attr("bit"), 10, 8)
+			if err != nil || bit < 2 || bit > 8 || bits.attr("fx") == "1" {
+				continue
+			}
+			name := bits.text("BitsShortName")
+			if isSpareName(name) {
+				continue
+			}
+			indicators = append(indicators, indicator{frn: uint8(i*7 + 9 - int(bit)), name: name})
+		}
+	}
+	sort.Slice(indicators, func(i, j int) bool { return indicators[i].frn < indicators[j].frn })
+	if len(indicators) != len(n.Nodes)-1 {
+		return fmt.Errorf("%w: %s primary subfield does not match the subfields", ErrSpecInvalid, field.DataItem)
+	}
+
+	field.Type = uap.Compound
+	for i, ind := range indicators {
+		for uint8(len(field.Compound))+1 < ind.frn {
+			field.Compound = append(field.Compound, uap.DataField{FRN: uint8(len(field.Compound)) + 1, Type: uap.Spare})
+		}
+		sub := uap.DataField{FRN: ind.frn, DataItem: ind.name}
+		if err := xmlFormat(&n.Nodes[i+1], &sub); err != nil {
+			return err
+		}
+		field.Compound = append(field.Compound, sub)
+	}
+	return nil
+}
+
+func xmlLength(n *xmlNode) (uint8, error) {
+	size, err := strconv.ParseUint(n.attr("length"), 10, 8)
+	if err != nil || size == 0 {
+		return 0, ErrSpecInvalid
+	}
+	return uint8(size), nil
+}
+
+// xmlSubFields appends the subfields of the Bits of a Fixed element, FX and spare bits are not declared.
+func xmlSubFields(n *xmlNode, part uint8, out *[]uap.SubField) error {
+	for _, bits := range n.children("Bits") {
+		name := bits.text("BitsShortName")
+		if bits.attr("fx") == "1" || isSpareName(name) || strings.EqualFold(name, "FX") {
+			continue
+		}
+		sf := uap.SubField{Name: name, Part: part}
+		if bit := bits.attr("bit"); bit != "" {
+			tmp, err := strconv.ParseUint(bit, 10, 8)
+			if err != nil {
+				return fmt.Errorf("%w: bits of %s", ErrSpecInvalid, name)
+			}
+			sf.From, sf.To = uint8(tmp), uint8(tmp)
+		} else {
+			from, err1 := strconv.ParseUint(bits.attr("from"), 10, 8)
+			to, err2 := strconv.ParseUint(bits.attr("to"), 10, 8)
+			if err1 != nil || err2 != nil {
+				return fmt.Errorf("%w: bits of %s", ErrSpecInvalid, name)
+			}
+			if from < to {
+				from, to = to, from
+			}
+			sf.From, sf.To = uint8(from), uint8(to)
+		}
+		if sf.To == 0 || sf.From-sf.To >= 64 {
+			continue
+		}
+
+		switch bits.attr("encode") {
+		case "signed":
+			sf.Signed = true
+		case "6bitschar":
+			sf.Format = uap.ICAO6
+		case "octal":
+			sf.Format = uap.Octal
+		case "ascii":
+			sf.Format = uap.ASCII
+		case "hex":
+			sf.Format = uap.Hex
+		}
+		if unit := bits.child("BitsUnit"); unit != nil {
+			sf.Unit = strings.TrimSpace(unit.Text)
+			if scale := unit.attr("scale"); scale != "" {
+				tmp, err := strconv.ParseFloat(scale, 64)
+				if err != nil {
+					return fmt.Errorf("%w: scale of %s", ErrSpecInvalid, name)
+				}
+				sf.Scale = tmp
+			}
+		}
+		for _, v := range bits.children("BitsValue") {
+			val, err := strconv.ParseUint(v.attr("val"), 10, 64)
+			if err != nil {
+				return fmt.Errorf("%w: value of %s", ErrSpecInvalid, name)
+			}
+			if sf.Enum == nil {
+				sf.Enum = make(map[uint64]string)
+			}
+			sf.Enum[val] = strings.TrimSpace(v.Text)
+		}
+		*out = append(*out, sf)
+	}
+	return nil
+}
+
+func isSpareName(name string) bool {
+	return name == "" || strings.EqualFold(name, "spare")
+}